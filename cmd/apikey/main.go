@@ -0,0 +1,126 @@
+// Command apikey mints, lists and revokes the API keys services call the API
+// with. It uses the database of the service's configuration:
+//
+//	apikey mint -name importer -scopes posts:read,posts:write,posts:impersonate -rate-limit 600
+//	apikey list
+//	apikey revoke <id>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/config"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/internal/usecase/repo"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const _usage = `usage:
+  apikey mint -name NAME -scopes SCOPE[,SCOPE...] [-rate-limit N]
+  apikey list
+  apikey revoke ID
+
+scopes: posts:read, posts:write, posts:impersonate, admin`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		log.Fatal(_usage)
+	}
+
+	// Configuration
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	if cfg.Storage.Driver != config.StoragePostgres {
+		log.Fatalf("apikey: keys are kept in postgres, not with the %q storage driver", cfg.Storage.Driver)
+	}
+
+	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(1))
+	if err != nil {
+		log.Fatalf("apikey - postgres.New: %s", err)
+	}
+	defer pg.Close()
+
+	keys := usecase.NewAPIKey(repo.NewAPIKey(pg))
+	ctx := context.Background()
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "mint":
+		err = mint(ctx, keys, args)
+	case "list":
+		err = list(ctx, keys)
+	case "revoke":
+		if len(args) != 1 {
+			log.Fatal(_usage)
+		}
+		err = keys.RevokeAPIKey(ctx, args[0])
+	default:
+		log.Fatal(_usage)
+	}
+
+	if err != nil {
+		log.Fatalf("apikey %s: %s", os.Args[1], err)
+	}
+}
+
+func mint(ctx context.Context, keys usecase.APIKey, args []string) error {
+	var (
+		fs     = flag.NewFlagSet("mint", flag.ExitOnError)
+		name   = fs.String("name", "", "what the key is for")
+		scopes = fs.String("scopes", entity.ScopePostsRead, "comma-separated scopes")
+		limit  = fs.Int("rate-limit", 0, "requests a minute, 0 for no limit")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, token, err := keys.MintAPIKey(ctx, &entity.APIKey{
+		Name:      *name,
+		Scopes:    strings.Split(*scopes, ","),
+		RateLimit: *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("id:    %s\nname:  %s\nkey:   %s\n\n", key.Id, key.Name, token)
+	fmt.Println("Store the key now, it can't be shown again. Send it in the X-API-Key header.")
+
+	return nil
+}
+
+func list(ctx context.Context, keys usecase.APIKey) error {
+	res, err := keys.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tRATE LIMIT\tCREATED\tLAST USED\tREVOKED")
+
+	for _, k := range res.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", k.Id, k.Name, k.Prefix,
+			strings.Join(k.Scopes, ","), k.RateLimit, k.CreatedAt, dash(k.LastUsedAt), dash(k.RevokedAt))
+	}
+
+	return w.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
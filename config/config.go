@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -9,10 +10,22 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App  `yaml:"app"`
-		HTTP `yaml:"http"`
-		Log  `yaml:"logger"`
-		PG   `yaml:"postgres"`
+		App         `yaml:"app"`
+		HTTP        `yaml:"http"`
+		GRPC        `yaml:"grpc"`
+		Log         `yaml:"logger"`
+		Storage     `yaml:"storage"`
+		PG          `yaml:"postgres"`
+		Auth        `yaml:"auth"`
+		APIKeys     `yaml:"api_keys"`
+		RateLimit   `yaml:"rate_limit"`
+		Idempotency `yaml:"idempotency"`
+		Purge       `yaml:"purge"`
+		Cursor      `yaml:"cursor"`
+		Search      `yaml:"search"`
+		Scheduler   `yaml:"scheduler"`
+		Views       `yaml:"views"`
+		Cache       `yaml:"cache"`
 	}
 
 	// App -.
@@ -23,7 +36,13 @@ type (
 
 	// HTTP -.
 	HTTP struct {
-		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		Port           string   `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
+	}
+
+	// GRPC -.
+	GRPC struct {
+		Port string `env-required:"true" yaml:"port" env:"GRPC_PORT"`
 	}
 
 	// Log -,
@@ -31,13 +50,93 @@ type (
 		Level string `env-required:"true" yaml:"log_level" env:"LOG_LEVEL"`
 	}
 
+	// Storage -.
+	Storage struct {
+		Driver string `env-default:"postgres" yaml:"driver" env:"STORAGE_DRIVER"`
+	}
+
 	// PG -.
 	PG struct {
-		PoolMax int    `env-required:"true" yaml:"pool_max" env:"POOL_MAX"`
-		URL     string `env-required:"true" yaml:"pg_url" env:"PG_URL"`
+		PoolMax        int    `env-required:"true" yaml:"pool_max" env:"POOL_MAX"`
+		URL            string `env-required:"true" yaml:"pg_url" env:"PG_URL"`
+		IsolationLevel string `env-default:"read committed" yaml:"isolation_level" env:"PG_ISOLATION_LEVEL"`
+		TxRetries      int    `env-default:"3" yaml:"tx_retries" env:"PG_TX_RETRIES"`
+	}
+
+	// Auth configures the keys access tokens are verified with: an HMAC
+	// secret, a public key, a JWKS file or any mix of them, but at least one.
+	Auth struct {
+		HMACSecret string        `yaml:"hmac_secret" env:"AUTH_HMAC_SECRET"`
+		PublicKey  string        `yaml:"public_key" env:"AUTH_PUBLIC_KEY"`
+		JWKSFile   string        `yaml:"jwks_file" env:"AUTH_JWKS_FILE"`
+		Issuer     string        `yaml:"issuer" env:"AUTH_ISSUER"`
+		Audience   string        `yaml:"audience" env:"AUTH_AUDIENCE"`
+		Leeway     time.Duration `env-default:"30s" yaml:"leeway" env:"AUTH_LEEWAY"`
+	}
+
+	// APIKeys -.
+	APIKeys struct {
+		UsageFlushInterval time.Duration `env-default:"1m" yaml:"usage_flush_interval" env:"API_KEYS_USAGE_FLUSH_INTERVAL"`
+	}
+
+	// RateLimit -.
+	RateLimit struct {
+		Store    string            `env-default:"memory" yaml:"store" env:"RATE_LIMIT_STORE"`
+		Policies map[string]string `yaml:"policies" env:"RATE_LIMIT_POLICIES"`
+	}
+
+	// Idempotency -.
+	Idempotency struct {
+		TTL   time.Duration `env-default:"24h" yaml:"ttl" env:"IDEMPOTENCY_TTL"`
+		Lease time.Duration `env-default:"1m" yaml:"lease" env:"IDEMPOTENCY_LEASE"`
+	}
+
+	// Purge -.
+	Purge struct {
+		Interval  time.Duration `env-default:"1h" yaml:"interval" env:"PURGE_INTERVAL"`
+		Retention time.Duration `env-default:"720h" yaml:"retention" env:"PURGE_RETENTION"`
+	}
+
+	// Cursor -.
+	Cursor struct {
+		Secret string `env-required:"true" yaml:"secret" env:"CURSOR_SECRET"`
+	}
+
+	// Search -.
+	Search struct {
+		Language string `env-default:"english" yaml:"language" env:"SEARCH_LANGUAGE"`
+	}
+
+	// Scheduler -.
+	Scheduler struct {
+		Interval time.Duration `env-default:"1m" yaml:"interval" env:"SCHEDULER_INTERVAL"`
+	}
+
+	// Views -.
+	Views struct {
+		FlushInterval time.Duration `env-default:"10s" yaml:"flush_interval" env:"VIEWS_FLUSH_INTERVAL"`
+		Window        time.Duration `env-default:"30m" yaml:"window" env:"VIEWS_WINDOW"`
+	}
+
+	// Cache -.
+	Cache struct {
+		Size int           `env-default:"10000" yaml:"size" env:"CACHE_SIZE"`
+		TTL  time.Duration `env-default:"30s" yaml:"ttl" env:"CACHE_TTL"`
 	}
 )
 
+// Storage drivers.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// Rate limit stores.
+const (
+	RateLimitMemory   = "memory"
+	RateLimitPostgres = "postgres"
+)
+
 // NewConfig returns app config.
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
@@ -52,5 +151,32 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// validate checks the values the field tags can't: the intervals of the
+// background jobs, which tick with time.NewTicker, must be positive.
+func (cfg *Config) validate() error {
+	intervals := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"scheduler.interval", cfg.Scheduler.Interval},
+		{"purge.interval", cfg.Purge.Interval},
+		{"views.flush_interval", cfg.Views.FlushInterval},
+		{"api_keys.usage_flush_interval", cfg.APIKeys.UsageFlushInterval},
+	}
+
+	for _, i := range intervals {
+		if i.interval <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", i.name, i.interval)
+		}
+	}
+
+	return nil
+}
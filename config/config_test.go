@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *Config {
+		cfg := &Config{}
+		cfg.Scheduler.Interval = time.Minute
+		cfg.Purge.Interval = time.Hour
+		cfg.Views.FlushInterval = 10 * time.Second
+		cfg.APIKeys.UsageFlushInterval = time.Minute
+
+		return cfg
+	}
+
+	require.NoError(t, valid().validate())
+
+	tests := []struct {
+		name string
+		mod  func(*Config)
+	}{
+		{"scheduler.interval", func(cfg *Config) { cfg.Scheduler.Interval = 0 }},
+		{"purge.interval", func(cfg *Config) { cfg.Purge.Interval = 0 }},
+		{"views.flush_interval", func(cfg *Config) { cfg.Views.FlushInterval = 0 }},
+		{"api_keys.usage_flush_interval", func(cfg *Config) { cfg.APIKeys.UsageFlushInterval = 0 }},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := valid()
+			tc.mod(cfg)
+
+			err := cfg.validate()
+			require.ErrorContains(t, err, tc.name+" must be positive")
+		})
+	}
+}
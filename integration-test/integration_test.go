@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	. "github.com/Eun/go-hit"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 const (
@@ -17,9 +19,19 @@ const (
 
 	// HTTP REST
 	basePath = "/http://" + host + "/v1"
+
+	userId = "d0b69f3b-2021-4d91-8e13-c243d9eb5292"
 )
 
+// jwtSecret signs the tokens of the tests. It must be the AUTH_HMAC_SECRET
+// of the service under test.
+var jwtSecret = os.Getenv("AUTH_HMAC_SECRET")
+
 func TestMain(m *testing.M) {
+	if jwtSecret == "" {
+		log.Fatal("Integration tests: AUTH_HMAC_SECRET is not set")
+	}
+
 	err := healthCheck(attempts)
 	if err != nil {
 		log.Fatalf("Integration tests: host %s is not available: %s", host, err)
@@ -48,42 +60,63 @@ func healthCheck(attempts int) error {
 	return err
 }
 
+func bearer(t *testing.T) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   userId,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte(jwtSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return "Bearer " + token
+}
+
 // HTTP POST: /post/create.
 func TestHTTPCreatePost(t *testing.T) {
 	body := `{
-		"user_id": "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
 		"content": "This is the content of post 13.",
 		"title": "Post 13",
-		"likes": 20,
-		"dislikes": 6,
-		"views": 100,
-		"category": "Nature"
+		"category": "Nature",
+		"status": "published"
 	}`
 	Test(t, 
 		Description("Create post Success"),
 		Post(basePath+"/post/create"), 
 		Send().Headers("Content-Type").Add("application/json"), 
+		Send().Headers("Authorization").Add(bearer(t)),
 		Send().Body().String(body), 
 		Expect().Status().Equal(http.StatusOK), 
 		Expect().Body().JSON().JQ(".title").Equal("Post 13"),	
+		Expect().Body().JSON().JQ(".status").Equal("published"),
+		Expect().Body().JSON().JQ(".user_id").Equal(userId),
+	)
+
+	Test(t,
+		Description("Create post Unauthorized"),
+		Post(basePath+"/post/create"),
+		Send().Headers("Content-Type").Add("application/json"),
+		Send().Body().String(body),
+		Expect().Status().Equal(http.StatusUnauthorized),
+		Expect().Headers("WWW-Authenticate").Equal(`Bearer realm="post-service"`),
 	)
 
 	body = `{
-		"user_id": "",
 		"content": "This is the content of post 13.",
 		"title": "Post 13",
-		"likes": 20,
-		"dislikes": 6,
-		"views": 100,
-		"category": "Nature"
+		"category": ""
 	}`
 	Test(t, 
 		Description("Create post Fail"),
 		Post(basePath+"/post/create"), 
 		Send().Headers("Content-Type").Add("application/json"), 
+		Send().Headers("Authorization").Add(bearer(t)),
 		Send().Body().String(body), 
-		Expect().Status().Equal(http.StatusBadRequest), 
-		Expect().Body().JSON().JQ(".error").Equal("invalid request body"),	
+		Expect().Status().Equal(http.StatusUnprocessableEntity), 
+		Expect().Body().JSON().JQ(".errors[0].field").Equal("category"),
+		Expect().Body().JSON().JQ(".errors[0].code").Equal("required"),
 	)
 }
 
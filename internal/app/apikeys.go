@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"time"
+)
+
+const _keyUsageFlushTimeout = 5 * time.Second
+
+// runKeyUsageFlusher saves the last use of the API keys every interval until
+// ctx is cancelled. The last uses are saved by flushKeyUsage on shutdown.
+func runKeyUsageFlusher(ctx context.Context, l logger.Interface, k usecase.APIKey, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			flushKeyUsage(ctx, l, k)
+		}
+	}
+}
+
+// flushKeyUsage saves the last use of the API keys, giving up after
+// _keyUsageFlushTimeout.
+func flushKeyUsage(ctx context.Context, l logger.Interface, k usecase.APIKey) {
+	ctx, cancel := context.WithTimeout(ctx, _keyUsageFlushTimeout)
+	defer cancel()
+
+	flushed, err := k.FlushAPIKeyUsage(ctx)
+	if err != nil {
+		l.Error(fmt.Errorf("app - flushKeyUsage - k.FlushAPIKeyUsage: %w", err))
+
+		return
+	}
+
+	if flushed > 0 {
+		l.Debug("app - flushKeyUsage - saved the last use of %d keys", flushed)
+	}
+}
@@ -1,16 +1,25 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"fourth-exam/post-service-clean-arch/config"
+	grpcctrl "fourth-exam/post-service-clean-arch/internal/controller/grpc"
 	v1 "fourth-exam/post-service-clean-arch/internal/controller/http/v1"
+	"fourth-exam/post-service-clean-arch/internal/entity"
 	"fourth-exam/post-service-clean-arch/internal/usecase"
 	"fourth-exam/post-service-clean-arch/internal/usecase/repo"
+	"fourth-exam/post-service-clean-arch/pkg/cache"
+	"fourth-exam/post-service-clean-arch/pkg/cursor"
+	"fourth-exam/post-service-clean-arch/pkg/grpcserver"
 	"fourth-exam/post-service-clean-arch/pkg/httpserver"
+	"fourth-exam/post-service-clean-arch/pkg/idempotency"
 	"fourth-exam/post-service-clean-arch/pkg/logger"
 	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/gin-gonic/gin"
@@ -21,31 +30,151 @@ func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
 	// Repository
-	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
-	if err != nil {
-		l.Fatal(fmt.Errorf("app - Run - postgres.New: %w", err))
+	var (
+		postRepo    usecase.PostRepo
+		commentRepo usecase.CommentRepo
+		apiKeyRepo  usecase.APIKeyRepo
+		idemStore   idempotency.Store
+		txManager   usecase.TxManager
+		pg          *postgres.Postgres
+		err         error
+	)
+
+	switch cfg.Storage.Driver {
+	case config.StorageMemory:
+		mem := repo.NewMemory()
+		postRepo, commentRepo, txManager = mem, repo.NewMemoryComment(mem), mem
+		apiKeyRepo = repo.NewMemoryAPIKey(mem)
+		idemStore = idempotency.NewMemory(
+			idempotency.TTL(cfg.Idempotency.TTL),
+			idempotency.Lease(cfg.Idempotency.Lease),
+		)
+	case config.StoragePostgres:
+		pg, err = postgres.New(cfg.PG.URL,
+			postgres.MaxPoolSize(cfg.PG.PoolMax),
+			postgres.IsolationLevel(cfg.PG.IsolationLevel),
+			postgres.TxRetries(cfg.PG.TxRetries),
+		)
+		if err != nil {
+			l.Fatal(fmt.Errorf("app - Run - postgres.New: %w", err))
+		}
+		defer pg.Close()
+
+		if !entity.IsSearchLanguage(cfg.Search.Language) {
+			l.Fatal(fmt.Errorf("app - Run: unknown search language %q", cfg.Search.Language))
+		}
+
+		posts := repo.New(pg, repo.SearchLanguage(cfg.Search.Language))
+
+		reindexed, err := posts.Reindex(context.Background())
+		if err != nil {
+			l.Fatal(fmt.Errorf("app - Run - posts.Reindex: %w", err))
+		}
+		if reindexed > 0 {
+			l.Info("app - Run - re-indexed %d posts in %s", reindexed, cfg.Search.Language)
+		}
+
+		postRepo = posts
+		commentRepo, txManager = repo.NewComment(pg), pg.TxManager
+		apiKeyRepo = repo.NewAPIKey(pg)
+		idemStore = idempotency.NewPostgres(pg,
+			idempotency.TTL(cfg.Idempotency.TTL),
+			idempotency.Lease(cfg.Idempotency.Lease),
+		)
+	default:
+		l.Fatal(fmt.Errorf("app - Run: unknown storage driver %q", cfg.Storage.Driver))
+	}
+
+	if cfg.Cache.TTL > 0 {
+		postRepo = repo.NewCached(postRepo, cache.NewLRU(cfg.Cache.Size), cfg.Cache.TTL)
 	}
-	defer pg.Close()
 
 	// Use case
 	postUseCase := usecase.New(
-		repo.New(pg),
+		postRepo,
+		cursor.New(cfg.Cursor.Secret),
+		txManager,
+		usecase.ViewWindow(cfg.Views.Window),
 	)
+	commentUseCase := usecase.NewComment(commentRepo)
+	apiKeyUseCase := usecase.NewAPIKey(apiKeyRepo)
+
+	// Authentication
+	verifier, err := newVerifier(cfg.Auth)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - newVerifier: %w", err))
+	}
+
+	// Rate limiting
+	var limiter ratelimit.Store
+
+	switch cfg.RateLimit.Store {
+	case config.RateLimitMemory:
+		limiter = ratelimit.NewMemory()
+	case config.RateLimitPostgres:
+		if pg == nil {
+			l.Fatal(fmt.Errorf("app - Run: the postgres rate limit store needs the postgres storage driver"))
+		}
+		limiter = ratelimit.NewPostgres(pg)
+	default:
+		l.Fatal(fmt.Errorf("app - Run: unknown rate limit store %q", cfg.RateLimit.Store))
+	}
+
+	policies := make(map[string]ratelimit.Rate, len(cfg.RateLimit.Policies))
+	for group, s := range cfg.RateLimit.Policies {
+		policies[group], err = ratelimit.ParseRate(s)
+		if err != nil {
+			l.Fatal(fmt.Errorf("app - Run - rate limit policy %s: %w", group, err))
+		}
+	}
+
+	// Background jobs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var jobs sync.WaitGroup
+
+	background := func(job func()) {
+		jobs.Add(1)
+
+		go func() {
+			defer jobs.Done()
+			job()
+		}()
+	}
+
+	background(func() { runPurger(ctx, l, postUseCase, cfg.Purge.Interval, cfg.Purge.Retention) })
+	background(func() { runScheduler(ctx, l, postUseCase, cfg.Scheduler.Interval) })
+	background(func() { runViewFlusher(ctx, l, postUseCase, cfg.Views.FlushInterval) })
+	background(func() { runKeyUsageFlusher(ctx, l, apiKeyUseCase, cfg.APIKeys.UsageFlushInterval) })
 
 	// HTTP Server
 	handler := gin.New()
-	v1.NewRouter(handler, l, postUseCase)
+	if err = handler.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		l.Fatal(fmt.Errorf("app - Run - handler.SetTrustedProxies: %w", err))
+	}
+	v1.NewRouter(handler, l, postUseCase, commentUseCase, apiKeyUseCase, verifier, limiter, policies, idemStore)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
+	// gRPC Server
+	grpcServer := grpcserver.New(
+		grpcserver.Port(cfg.GRPC.Port),
+		grpcserver.UnaryInterceptors(grpcctrl.Recovery(l), grpcctrl.Authenticate(verifier, l)),
+	)
+	grpcctrl.NewRouter(grpcServer.App, l, postUseCase)
+	grpcServer.Start()
+
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	select {
-	case s := <- interrupt:
+	case s := <-interrupt:
 		l.Info("app - Run - signal: " + s.String())
-	case err = <- httpServer.Notify():
+	case err = <-httpServer.Notify():
 		l.Error(fmt.Errorf("app - Run - httpServer.Notify: %w", err))
+	case err = <-grpcServer.Notify():
+		l.Error(fmt.Errorf("app - Run - grpcServer.Notify: %w", err))
 	}
 
 	// Shutdown
@@ -53,4 +182,17 @@ func Run(cfg *config.Config) {
 	if err != nil {
 		l.Error(fmt.Errorf("app - run - httpServer.Shutdown: %w", err))
 	}
+
+	err = grpcServer.Shutdown()
+	if err != nil {
+		l.Error(fmt.Errorf("app - run - grpcServer.Shutdown: %w", err))
+	}
+
+	// Stop the jobs before the last flushes, so they don't race a flush
+	// still in progress.
+	cancel()
+	jobs.Wait()
+
+	flushViews(context.Background(), l, postUseCase)
+	flushKeyUsage(context.Background(), l, apiKeyUseCase)
 }
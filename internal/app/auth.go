@@ -0,0 +1,20 @@
+package app
+
+import (
+	"fourth-exam/post-service-clean-arch/config"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+)
+
+// newVerifier verifies access tokens with the keys cfg configures. Unset
+// ones are left out, so HS256 tokens are only accepted with an HMAC secret;
+// jwtauth.New fails with jwtauth.ErrNoKeys when there is no key at all.
+func newVerifier(cfg config.Auth) (*jwtauth.Verifier, error) {
+	return jwtauth.New(
+		jwtauth.HMACSecret(cfg.HMACSecret),
+		jwtauth.PublicKey(cfg.PublicKey),
+		jwtauth.JWKSFile(cfg.JWKSFile),
+		jwtauth.Issuer(cfg.Issuer),
+		jwtauth.Audience(cfg.Audience),
+		jwtauth.Leeway(cfg.Leeway),
+	)
+}
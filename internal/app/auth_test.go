@@ -0,0 +1,59 @@
+package app
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fourth-exam/post-service-clean-arch/config"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const _secret = "0123456789abcdef0123456789abcdef"
+
+func TestNewVerifierWithPublicKeyOnly(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	v, err := newVerifier(config.Auth{
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		Leeway:    30 * time.Second,
+	})
+	require.NoError(t, err, "no HMAC secret is needed")
+
+	sign := func(method jwt.SigningMethod, key interface{}) string {
+		token, err := jwt.NewWithClaims(method, &jwtauth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}).SignedString(key)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	_, err = v.Verify(sign(jwt.SigningMethodES256, key))
+	require.NoError(t, err)
+
+	_, err = v.Verify(sign(jwt.SigningMethodHS256, []byte(_secret)))
+	require.ErrorIs(t, err, jwtauth.ErrInvalidToken, "HS256 isn't accepted without a secret")
+}
+
+func TestNewVerifierWithoutKeys(t *testing.T) {
+	t.Parallel()
+
+	_, err := newVerifier(config.Auth{Leeway: 30 * time.Second})
+	require.ErrorIs(t, err, jwtauth.ErrNoKeys)
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"time"
+)
+
+// _purger is the principal the purger acts as: purging is admin only.
+var _purger = &entity.Principal{Roles: []string{entity.RoleAdmin}}
+
+// runPurger hard-deletes posts that stayed soft-deleted longer than retention,
+// checking every interval until ctx is cancelled.
+func runPurger(ctx context.Context, l logger.Interface, t usecase.Post, interval, retention time.Duration) {
+	ctx = entity.ContextWithPrincipal(ctx, _purger)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := t.PurgeDeletedPosts(ctx, retention)
+			if err != nil {
+				l.Error(fmt.Errorf("app - runPurger - t.PurgeDeletedPosts: %w", err))
+
+				continue
+			}
+
+			if purged > 0 {
+				l.Info("app - runPurger - purged %d deleted posts", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"time"
+)
+
+// runScheduler publishes scheduled posts once their time has come,
+// checking every interval until ctx is cancelled.
+func runScheduler(ctx context.Context, l logger.Interface, t usecase.Post, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := t.PublishScheduledPosts(ctx)
+			if err != nil {
+				l.Error(fmt.Errorf("app - runScheduler - t.PublishScheduledPosts: %w", err))
+
+				continue
+			}
+
+			if published > 0 {
+				l.Info("app - runScheduler - published %d scheduled posts", published)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"time"
+)
+
+const _viewFlushTimeout = 5 * time.Second
+
+// runViewFlusher saves the views counted in memory every interval until ctx
+// is cancelled. The last views are saved by flushViews on shutdown.
+func runViewFlusher(ctx context.Context, l logger.Interface, t usecase.Post, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			flushViews(ctx, l, t)
+		}
+	}
+}
+
+// flushViews saves the views counted in memory, giving up after _viewFlushTimeout.
+func flushViews(ctx context.Context, l logger.Interface, t usecase.Post) {
+	ctx, cancel := context.WithTimeout(ctx, _viewFlushTimeout)
+	defer cancel()
+
+	flushed, err := t.FlushViews(ctx)
+	if err != nil {
+		l.Error(fmt.Errorf("app - flushViews - t.FlushViews: %w", err))
+
+		return
+	}
+
+	if flushed > 0 {
+		l.Debug("app - flushViews - saved %d views", flushed)
+	}
+}
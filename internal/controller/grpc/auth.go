@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"strings"
+
+	postv1 "fourth-exam/post-service-clean-arch/api/post/v1"
+
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// _publicMethods can be called without a token, like the read routes of the
+// HTTP API.
+var _publicMethods = map[string]bool{
+	postv1.PostService_GetPost_FullMethodName:     true,
+	postv1.PostService_ListPosts_FullMethodName:   true,
+	postv1.PostService_SearchPosts_FullMethodName: true,
+}
+
+// Authenticate verifies the bearer token in the authorization metadata and
+// puts its principal in the call context. Calls to anything but
+// _publicMethods need one.
+func Authenticate(v *jwtauth.Verifier, l logger.Interface) pbgrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (interface{}, error) {
+		var header string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				header = values[0]
+			}
+		}
+
+		if header == "" {
+			if !_publicMethods[info.FullMethod] {
+				return nil, status.Error(codes.Unauthenticated, "unauthorized")
+			}
+
+			return handler(ctx, req)
+		}
+
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			return nil, status.Error(codes.Unauthenticated, "expected a bearer token")
+		}
+
+		claims, err := v.Verify(strings.TrimSpace(token))
+		if err != nil {
+			l.Error(err, "grpc - authenticate")
+
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		ctx = entity.ContextWithPrincipal(ctx, &entity.Principal{
+			UserId: claims.Subject,
+			Roles:  claims.Roles,
+		})
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,174 @@
+package grpc
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+	"net"
+	"testing"
+	"time"
+
+	postv1 "fourth-exam/post-service-clean-arch/api/post/v1"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	_secret      = "0123456789abcdef0123456789abcdef"
+	_otherSecret = "fedcba9876543210fedcba9876543210"
+	_userId      = "d0b69f3b-2021-4d91-8e13-c243d9eb5292"
+)
+
+// nopLogger discards what it is given.
+type nopLogger struct{}
+
+func (nopLogger) Debug(interface{}, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})       {}
+func (nopLogger) Warn(string, ...interface{})       {}
+func (nopLogger) Error(interface{}, ...interface{}) {}
+func (nopLogger) Fatal(interface{}, ...interface{}) {}
+
+// fakePosts answers the public reads and CreatePost, which echoes the post
+// with the author the call was authenticated as. Other methods of
+// usecase.Post aren't implemented.
+type fakePosts struct {
+	usecase.Post
+}
+
+func (fakePosts) ViewPost(_ context.Context, id, _ string) (*entity.Post, error) {
+	return &entity.Post{Id: id}, nil
+}
+
+func (fakePosts) ListPostsByCursor(context.Context, *entity.GetListFilter) (*entity.Posts, error) {
+	return &entity.Posts{}, nil
+}
+
+func (fakePosts) SearchPosts(context.Context, *entity.SearchFilter) (*entity.SearchResults, error) {
+	return &entity.SearchResults{}, nil
+}
+
+func (fakePosts) CreatePost(_ context.Context, post *entity.Post) (*entity.Post, error) {
+	return post, nil
+}
+
+// dial serves the router behind the interceptors of the app on an in-memory
+// connection.
+func dial(t *testing.T) *pbgrpc.ClientConn {
+	t.Helper()
+
+	v, err := jwtauth.New(jwtauth.HMACSecret(_secret))
+	require.NoError(t, err)
+
+	ln := bufconn.Listen(1 << 20)
+
+	srv := pbgrpc.NewServer(pbgrpc.ChainUnaryInterceptor(Recovery(nopLogger{}), Authenticate(v, nopLogger{})))
+	NewRouter(srv, nopLogger{}, fakePosts{})
+
+	go srv.Serve(ln) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	conn, err := pbgrpc.NewClient("passthrough:///bufnet",
+		pbgrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		pbgrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// withBearer returns ctx sending the bearer token of user, signed with secret.
+func withBearer(ctx context.Context, t *testing.T, user, secret string) context.Context {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtauth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestAuthenticatePublicMethods(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		client = postv1.NewPostServiceClient(dial(t))
+	)
+
+	_, err := client.GetPost(ctx, &postv1.GetPostRequest{Id: "id"})
+	require.NoError(t, err)
+	_, err = client.ListPosts(ctx, &postv1.ListPostsRequest{})
+	require.NoError(t, err)
+	_, err = client.SearchPosts(ctx, &postv1.SearchPostsRequest{Query: "q"})
+	require.NoError(t, err)
+
+	_, err = client.GetPost(withBearer(ctx, t, _userId, _otherSecret), &postv1.GetPostRequest{Id: "id"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err), "a token sent must be valid")
+}
+
+func TestAuthenticateRejectsWrites(t *testing.T) {
+	t.Parallel()
+
+	conn := dial(t)
+
+	for _, m := range postv1.PostService_ServiceDesc.Methods {
+		method := "/" + postv1.PostService_ServiceDesc.ServiceName + "/" + m.MethodName
+		if _publicMethods[method] {
+			continue
+		}
+
+		err := conn.Invoke(context.Background(), method, &emptypb.Empty{}, &emptypb.Empty{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err), method)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		client = postv1.NewPostServiceClient(dial(t))
+		create = &postv1.CreatePostRequest{Title: "Title"}
+	)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{"no token", ctx, codes.Unauthenticated},
+		{"not a bearer token", metadata.AppendToOutgoingContext(ctx, "authorization", "Basic dXNlcjpwYXNz"), codes.Unauthenticated},
+		{"empty bearer token", metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer  "), codes.Unauthenticated},
+		{"invalid token", withBearer(ctx, t, _userId, _otherSecret), codes.Unauthenticated},
+		{"valid token", withBearer(ctx, t, _userId, _secret), codes.OK},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			post, err := client.CreatePost(tc.ctx, create)
+			require.Equal(t, tc.code, status.Code(err))
+
+			if tc.code == codes.OK {
+				require.Equal(t, _userId, post.GetUserId(), "the post is authored by the caller")
+			}
+		})
+	}
+}
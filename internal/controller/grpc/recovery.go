@@ -0,0 +1,27 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"runtime/debug"
+
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Recovery turns a panicking call into an Internal error instead of taking
+// the whole server down, like gin.Recovery does for the HTTP API.
+func Recovery(l logger.Interface) pbgrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *pbgrpc.UnaryServerInfo, handler pbgrpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				l.Error(fmt.Errorf("panic: %v\n%s", r, debug.Stack()), "grpc - "+info.FullMethod)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
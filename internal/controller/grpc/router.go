@@ -0,0 +1,19 @@
+package grpc
+
+import (
+	v1 "fourth-exam/post-service-clean-arch/internal/controller/grpc/v1"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewRouter -.
+func NewRouter(app *pbgrpc.Server, l logger.Interface, t usecase.Post) {
+	{
+		v1.NewPostRoutes(app, t, l)
+	}
+
+	reflection.Register(app)
+}
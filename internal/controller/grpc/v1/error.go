@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"errors"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serviceError maps a use case error to its gRPC status, the counterpart of
+// the HTTP serviceErrorResponse. Field-level validation errors carry a
+// BadRequest detail; anything else unknown is Internal with msg, so driver
+// details never reach the client.
+func serviceError(err error, msg string) error {
+	var validationErr *entity.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		return validationStatus(validationErr)
+	case errors.Is(err, entity.ErrNotFound):
+		return status.Error(codes.NotFound, entity.ErrNotFound.Error())
+	case errors.Is(err, entity.ErrVersionConflict):
+		return status.Error(codes.Aborted, entity.ErrVersionConflict.Error())
+	case errors.Is(err, entity.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, entity.ErrInvalidTransition.Error())
+	case errors.Is(err, entity.ErrConflict):
+		return status.Error(codes.AlreadyExists, entity.ErrConflict.Error())
+	case errors.Is(err, entity.ErrValidation):
+		return status.Error(codes.InvalidArgument, entity.ErrValidation.Error())
+	case errors.Is(err, entity.ErrForbidden):
+		return status.Error(codes.PermissionDenied, entity.ErrForbidden.Error())
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func validationStatus(err *entity.ValidationError) error {
+	details := &errdetails.BadRequest{}
+	for _, f := range err.Errors {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Code,
+		})
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, entity.ErrValidation.Error()).WithDetails(details)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,359 @@
+package v1
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"net"
+	"time"
+
+	postv1 "fourth-exam/post-service-clean-arch/api/post/v1"
+
+	"github.com/google/uuid"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+)
+
+type postRoutes struct {
+	postv1.UnimplementedPostServiceServer
+
+	t usecase.Post
+	l logger.Interface
+}
+
+// NewPostRoutes -.
+func NewPostRoutes(app *pbgrpc.Server, t usecase.Post, l logger.Interface) {
+	postv1.RegisterPostServiceServer(app, &postRoutes{t: t, l: l})
+}
+
+// CreatePost -.
+func (p *postRoutes) CreatePost(ctx context.Context, req *postv1.CreatePostRequest) (*postv1.Post, error) {
+	post := &entity.Post{
+		Id:       uuid.New().String(),
+		UserId:   principal(ctx).UserId,
+		Title:    req.GetTitle(),
+		Content:  req.GetContent(),
+		Category: req.GetCategory(),
+		Tags:     req.GetTags(),
+		Status:   req.GetStatus(),
+	}
+	if req.GetPublishAt() != nil {
+		post.PublishedAt = req.GetPublishAt().AsTime().Format(time.RFC3339Nano)
+	}
+
+	post, err := p.t.CreatePost(ctx, post)
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - create post")
+
+		return nil, serviceError(err, "create post service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// GetPost -.
+func (p *postRoutes) GetPost(ctx context.Context, req *postv1.GetPostRequest) (*postv1.Post, error) {
+	post, err := p.t.ViewPost(ctx, req.GetId(), peerHost(ctx))
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - get post")
+
+		return nil, serviceError(err, "get post service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// UpdatePost -.
+func (p *postRoutes) UpdatePost(ctx context.Context, req *postv1.UpdatePostRequest) (*postv1.Post, error) {
+	if req.GetVersion() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	post, err := p.t.UpdatePost(ctx, &entity.Post{
+		Id:       req.GetId(),
+		Title:    req.GetTitle(),
+		Content:  req.GetContent(),
+		Category: req.GetCategory(),
+		Tags:     req.GetTags(),
+		Version:  req.GetVersion(),
+	})
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - update post")
+
+		return nil, serviceError(err, "update post service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// DeletePost -.
+func (p *postRoutes) DeletePost(ctx context.Context, req *postv1.DeletePostRequest) (*emptypb.Empty, error) {
+	if req.GetVersion() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	err := p.t.DeletePost(ctx, req.GetId(), req.GetVersion())
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - delete post")
+
+		return nil, serviceError(err, "delete post service problems")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// PublishPost -.
+func (p *postRoutes) PublishPost(ctx context.Context, req *postv1.PublishPostRequest) (*postv1.Post, error) {
+	var at time.Time
+	if req.GetPublishAt() != nil {
+		at = req.GetPublishAt().AsTime()
+	}
+
+	post, err := p.t.PublishPost(ctx, req.GetId(), at)
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - publish post")
+
+		return nil, serviceError(err, "publish post service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// UnpublishPost -.
+func (p *postRoutes) UnpublishPost(ctx context.Context, req *postv1.UnpublishPostRequest) (*postv1.Post, error) {
+	post, err := p.t.UnpublishPost(ctx, req.GetId())
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - unpublish post")
+
+		return nil, serviceError(err, "unpublish post service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// ArchivePost -.
+func (p *postRoutes) ArchivePost(ctx context.Context, req *postv1.ArchivePostRequest) (*postv1.Post, error) {
+	post, err := p.t.ArchivePost(ctx, req.GetId())
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - archive post")
+
+		return nil, serviceError(err, "archive post service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// ListPosts -.
+func (p *postRoutes) ListPosts(ctx context.Context, req *postv1.ListPostsRequest) (*postv1.ListPostsResponse, error) {
+	limit, err := pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &entity.GetListFilter{
+		Limit:      limit,
+		Cursor:     req.GetPageToken(),
+		UserId:     req.GetUserId(),
+		Categories: req.GetCategories(),
+		Statuses:   []string{entity.StatusPublished},
+	}
+	if len(req.GetTagsAny()) > 0 {
+		filter.TagsAny = entity.NormalizeTags(req.GetTagsAny())
+	}
+	if len(req.GetTagsAll()) > 0 {
+		filter.TagsAll = entity.NormalizeTags(req.GetTagsAll())
+	}
+
+	posts, err := p.t.ListPostsByCursor(ctx, filter)
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - list posts")
+
+		return nil, serviceError(err, "list posts service problems")
+	}
+
+	resp := &postv1.ListPostsResponse{
+		Posts:         make([]*postv1.Post, 0, len(posts.Items)),
+		NextPageToken: posts.NextCursor,
+		PrevPageToken: posts.PrevCursor,
+	}
+	for _, post := range posts.Items {
+		resp.Posts = append(resp.Posts, toPost(post))
+	}
+
+	return resp, nil
+}
+
+// SearchPosts -.
+func (p *postRoutes) SearchPosts(ctx context.Context, req *postv1.SearchPostsRequest) (*postv1.SearchPostsResponse, error) {
+	limit, err := pageSize(req.GetPageSize())
+	if err != nil {
+		return nil, err
+	}
+
+	page := int64(req.GetPage())
+	switch {
+	case page == 0:
+		page = 1
+	case page < 0:
+		return nil, status.Error(codes.InvalidArgument, "page must be positive")
+	}
+
+	results, err := p.t.SearchPosts(ctx, &entity.SearchFilter{
+		GetListFilter: entity.GetListFilter{
+			Page:      page,
+			Limit:     limit,
+			Statuses:  []string{entity.StatusPublished},
+			WithTotal: true,
+		},
+		Query: req.GetQuery(),
+	})
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - search posts")
+
+		return nil, serviceError(err, "search posts service problems")
+	}
+
+	resp := &postv1.SearchPostsResponse{
+		Results: make([]*postv1.SearchResult, 0, len(results.Items)),
+		HasMore: results.HasMore,
+	}
+	if results.Total != nil {
+		resp.Total = *results.Total
+	}
+	for _, r := range results.Items {
+		resp.Results = append(resp.Results, &postv1.SearchResult{
+			Post:     toPost(r.Post),
+			Rank:     r.Rank,
+			Headline: r.Headline,
+		})
+	}
+
+	return resp, nil
+}
+
+// React -.
+func (p *postRoutes) React(ctx context.Context, req *postv1.ReactRequest) (*postv1.Post, error) {
+	var reaction string
+
+	switch req.GetReaction() {
+	case postv1.ReactRequest_REACTION_LIKE:
+		reaction = entity.ReactionLike
+	case postv1.ReactRequest_REACTION_DISLIKE:
+		reaction = entity.ReactionDislike
+	default:
+		return nil, status.Error(codes.InvalidArgument, "reaction is required")
+	}
+
+	post, err := p.t.React(ctx, &entity.Reaction{
+		PostId:   req.GetPostId(),
+		UserId:   principal(ctx).UserId,
+		Reaction: reaction,
+	})
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - react")
+
+		return nil, serviceError(err, "react service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// Unreact -.
+func (p *postRoutes) Unreact(ctx context.Context, req *postv1.UnreactRequest) (*postv1.Post, error) {
+	post, err := p.t.Unreact(ctx, &entity.Reaction{
+		PostId: req.GetPostId(),
+		UserId: principal(ctx).UserId,
+	})
+	if err != nil {
+		p.l.Error(err, "grpc - v1 - unreact")
+
+		return nil, serviceError(err, "unreact service problems")
+	}
+
+	return toPost(post), nil
+}
+
+// pageSize checks a requested page size, 0 meaning the default.
+func pageSize(size int32) (int64, error) {
+	switch {
+	case size == 0:
+		return _defaultPageSize, nil
+	case size < 0 || size > _maxPageSize:
+		return 0, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", _maxPageSize)
+	}
+
+	return int64(size), nil
+}
+
+// principal returns the caller, which the Authenticate interceptor
+// guarantees for every method that isn't public.
+func principal(ctx context.Context) *entity.Principal {
+	p, ok := entity.PrincipalFromContext(ctx)
+	if !ok {
+		return &entity.Principal{}
+	}
+
+	return p
+}
+
+// peerHost identifies the caller for view counting, like the client IP does
+// over HTTP.
+func peerHost(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		return pr.Addr.String()
+	}
+
+	return host
+}
+
+func toPost(post *entity.Post) *postv1.Post {
+	return &postv1.Post{
+		Id:            post.Id,
+		UserId:        post.UserId,
+		Title:         post.Title,
+		Content:       post.Content,
+		Category:      post.Category,
+		Tags:          post.Tags,
+		Likes:         post.Likes,
+		Dislikes:      post.Dislikes,
+		Views:         post.Views,
+		CommentsCount: post.CommentsCount,
+		Status:        post.Status,
+		PublishedAt:   timestamp(post.PublishedAt),
+		Version:       post.Version,
+		CreatedAt:     timestamp(post.CreatedAt),
+		UpdatedAt:     timestamp(post.UpdatedAt),
+		DeletedAt:     timestamp(post.DeletedAt),
+	}
+}
+
+// timestamp converts a post timestamp, formatted with entity.TimestampLayout,
+// leaving unset ones nil.
+func timestamp(s string) *timestamppb.Timestamp {
+	if s == "" {
+		return nil
+	}
+
+	t, err := time.Parse(entity.TimestampLayout, s)
+	if err != nil {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
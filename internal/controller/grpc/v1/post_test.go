@@ -0,0 +1,212 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	postv1 "fourth-exam/post-service-clean-arch/api/post/v1"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	pbgrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// nopLogger discards what it is given.
+type nopLogger struct{}
+
+func (nopLogger) Debug(interface{}, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})       {}
+func (nopLogger) Warn(string, ...interface{})       {}
+func (nopLogger) Error(interface{}, ...interface{}) {}
+func (nopLogger) Fatal(interface{}, ...interface{}) {}
+
+// fakePosts answers ViewPost with post or err and ListPostsByCursor with no
+// posts, recording the filter. Other methods of usecase.Post aren't
+// implemented.
+type fakePosts struct {
+	usecase.Post
+
+	post *entity.Post
+	err  error
+
+	mu     sync.Mutex
+	filter *entity.GetListFilter
+}
+
+func (f *fakePosts) ViewPost(context.Context, string, string) (*entity.Post, error) {
+	return f.post, f.err
+}
+
+func (f *fakePosts) ListPostsByCursor(_ context.Context, filter *entity.GetListFilter) (*entity.Posts, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.filter = filter
+
+	return &entity.Posts{}, nil
+}
+
+// dial serves the post routes of posts on an in-memory connection.
+func dial(t *testing.T, posts usecase.Post) postv1.PostServiceClient {
+	t.Helper()
+
+	ln := bufconn.Listen(1 << 20)
+
+	srv := pbgrpc.NewServer()
+	NewPostRoutes(srv, posts, nopLogger{})
+
+	go srv.Serve(ln) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	conn, err := pbgrpc.NewClient("passthrough:///bufnet",
+		pbgrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		pbgrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return postv1.NewPostServiceClient(conn)
+}
+
+func TestServiceError(t *testing.T) {
+	t.Parallel()
+
+	validation := &entity.ValidationError{}
+	validation.Add("title", entity.CodeRequired)
+	validation.Add("post_id", entity.CodeInvalidUUID)
+
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"not found", fmt.Errorf("repo: %w", entity.ErrNotFound), codes.NotFound, "not found"},
+		{"version conflict", entity.ErrVersionConflict, codes.Aborted, entity.ErrVersionConflict.Error()},
+		{"invalid transition", entity.ErrInvalidTransition, codes.FailedPrecondition, entity.ErrInvalidTransition.Error()},
+		{"conflict", fmt.Errorf("%w: taken", entity.ErrConflict), codes.AlreadyExists, "conflict"},
+		{"validation", fmt.Errorf("usecase: %w", entity.ErrValidation), codes.InvalidArgument, "validation failed"},
+		{"validation fields", fmt.Errorf("usecase: %w", validation), codes.InvalidArgument, "validation failed"},
+		{"forbidden", entity.ErrForbidden, codes.PermissionDenied, "forbidden"},
+		{"other", errors.New("connection refused"), codes.Internal, "get post service problems"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := dial(t, &fakePosts{err: tc.err}).GetPost(context.Background(), &postv1.GetPostRequest{Id: "id"})
+
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.code, st.Code())
+			require.Equal(t, tc.msg, st.Message())
+
+			if tc.name != "validation fields" {
+				require.Empty(t, st.Details())
+
+				return
+			}
+
+			require.Len(t, st.Details(), 1)
+			require.True(t, proto.Equal(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "title", Description: entity.CodeRequired},
+				{Field: "post_id", Description: entity.CodeInvalidUUID},
+			}}, st.Details()[0].(proto.Message)))
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size  int32
+		limit int64
+		code  codes.Code
+	}{
+		{0, _defaultPageSize, codes.OK},
+		{1, 1, codes.OK},
+		{_maxPageSize, _maxPageSize, codes.OK},
+		{_maxPageSize + 1, 0, codes.InvalidArgument},
+		{-1, 0, codes.InvalidArgument},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(fmt.Sprint(tc.size), func(t *testing.T) {
+			t.Parallel()
+
+			posts := &fakePosts{}
+
+			_, err := dial(t, posts).ListPosts(context.Background(), &postv1.ListPostsRequest{PageSize: tc.size})
+			require.Equal(t, tc.code, status.Code(err))
+
+			if tc.code == codes.OK {
+				require.Equal(t, tc.limit, posts.filter.Limit)
+				require.Equal(t, []string{entity.StatusPublished}, posts.filter.Statuses)
+			} else {
+				require.Nil(t, posts.filter, "the use case isn't called")
+			}
+		})
+	}
+}
+
+func TestToPost(t *testing.T) {
+	t.Parallel()
+
+	var (
+		created   = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		published = time.Date(2024, 3, 2, 12, 30, 0, 500, time.FixedZone("MSK", 3*60*60))
+		post      = &entity.Post{
+			Id:            "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+			UserId:        "0f4b1a8e-6b0a-4c55-9a3b-5e0f2b6c7d11",
+			Title:         "Title",
+			Content:       "Content",
+			Category:      "Nature",
+			Tags:          []string{"go", "sql"},
+			Likes:         3,
+			Dislikes:      1,
+			Views:         42,
+			CommentsCount: 2,
+			Status:        entity.StatusPublished,
+			PublishedAt:   published.Format(entity.TimestampLayout),
+			Version:       5,
+			CreatedAt:     created.Format(entity.TimestampLayout),
+			UpdatedAt:     "not a timestamp",
+		}
+	)
+
+	got, err := dial(t, &fakePosts{post: post}).GetPost(context.Background(), &postv1.GetPostRequest{Id: post.Id})
+	require.NoError(t, err)
+
+	require.Equal(t, post.Id, got.GetId())
+	require.Equal(t, post.UserId, got.GetUserId())
+	require.Equal(t, post.Title, got.GetTitle())
+	require.Equal(t, post.Content, got.GetContent())
+	require.Equal(t, post.Category, got.GetCategory())
+	require.Equal(t, post.Tags, got.GetTags())
+	require.Equal(t, [4]int64{3, 1, 42, 2}, [4]int64{got.GetLikes(), got.GetDislikes(), got.GetViews(), got.GetCommentsCount()})
+	require.Equal(t, post.Status, got.GetStatus())
+	require.Equal(t, post.Version, got.GetVersion())
+
+	require.True(t, published.Equal(got.GetPublishedAt().AsTime()))
+	require.True(t, created.Equal(got.GetCreatedAt().AsTime()))
+	require.Nil(t, got.GetUpdatedAt(), "malformed timestamps are left unset")
+	require.Nil(t, got.GetDeletedAt(), "unset timestamps stay unset")
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"errors"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyHeader carries the API key of a service caller.
+const apiKeyHeader = "X-API-Key"
+
+// _apiKeyChallenge is the WWW-Authenticate challenge for API keys.
+const _apiKeyChallenge = `APIKey realm="post-service"`
+
+// authenticateAPIKey authenticates requests carrying an API key and puts the
+// key's principal in the request context, like authenticate does for bearer
+// tokens; a request may carry one or the other. Reads need the posts:read
+// scope and writes posts:write, and keys with a rate limit are held to it.
+// Client IPs that fail to present a valid key too often are turned away.
+func authenticateAPIKey(k usecase.APIKey, limiter *rateLimiter, l logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader(apiKeyHeader)
+		if token == "" {
+			c.Next()
+
+			return
+		}
+
+		ctx := c.Request.Context()
+
+		if _, ok := entity.PrincipalFromContext(ctx); ok {
+			errorResponse(c, http.StatusBadRequest, "send either a bearer token or an API key, not both")
+
+			return
+		}
+
+		if !limiter.attempt(c) {
+			return
+		}
+
+		key, err := k.AuthenticateAPIKey(ctx, token)
+		if err != nil {
+			l.Error(err, "http - v1 - authenticateAPIKey")
+
+			if errors.Is(err, entity.ErrUnauthenticated) {
+				limiter.fail(c)
+				c.Header("WWW-Authenticate", _apiKeyChallenge)
+				errorResponse(c, http.StatusUnauthorized, "unauthorized")
+
+				return
+			}
+
+			errorResponse(c, http.StatusInternalServerError, "api key service problems")
+
+			return
+		}
+
+		if scope := requiredScope(c.Request.Method); !key.HasScope(scope) {
+			errorResponse(c, http.StatusForbidden, "api key lacks the "+scope+" scope")
+
+			return
+		}
+
+		if key.RateLimit > 0 && !limiter.allow(c, "apikey:"+key.Id, ratelimit.PerMinute(key.RateLimit)) {
+			return
+		}
+
+		c.Request = c.Request.WithContext(entity.ContextWithPrincipal(ctx, key.Principal()))
+
+		c.Next()
+	}
+}
+
+// requiredScope returns the scope an API key needs for a request with method.
+func requiredScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return entity.ScopePostsRead
+	default:
+		return entity.ScopePostsWrite
+	}
+}
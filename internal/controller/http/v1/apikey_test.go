@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const _userId = "d0b69f3b-2021-4d91-8e13-c243d9eb5292"
+
+var (
+	_readerKey  = &entity.APIKey{Id: "reader", Scopes: []string{entity.ScopePostsRead}}
+	_writerKey  = &entity.APIKey{Id: "writer", Scopes: []string{entity.ScopePostsWrite}}
+	_limitedKey = &entity.APIKey{Id: "limited", Scopes: []string{entity.ScopePostsRead}, RateLimit: 1}
+	_importer   = &entity.APIKey{Id: "importer", Scopes: []string{entity.ScopePostsWrite, entity.ScopePostsImpersonate}}
+)
+
+// apiKeyEngine authenticates requests like the /v1 group does. GET / answers
+// with the principal and POST / with the user the caller acts as, named by
+// the user_id query parameter.
+func apiKeyEngine(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	keys := fakeAPIKeys{
+		"reader":   _readerKey,
+		"writer":   _writerKey,
+		"limited":  _limitedKey,
+		"importer": _importer,
+	}
+	rl := &rateLimiter{store: ratelimit.NewMemory(), l: nopLogger{}}
+
+	e := gin.New()
+	h := e.Group("/", authenticate(verifier(t), nopLogger{}), authenticateAPIKey(keys, rl, nopLogger{}))
+	h.GET("/", func(c *gin.Context) { c.JSON(http.StatusOK, principal(c)) })
+	h.POST("/", requireAuth, func(c *gin.Context) {
+		if userId, ok := actingUser(c, c.Query("user_id")); ok {
+			c.String(http.StatusOK, userId)
+		}
+	})
+
+	return e
+}
+
+func serve(e *gin.Engine, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		header map[string]string
+		want   int
+	}{
+		{"no credentials", http.MethodGet, nil, http.StatusOK},
+		{"key with the scope", http.MethodGet, map[string]string{apiKeyHeader: "reader"}, http.StatusOK},
+		{"unknown key", http.MethodGet, map[string]string{apiKeyHeader: "nope"}, http.StatusUnauthorized},
+		{"key without the write scope", http.MethodPost, map[string]string{apiKeyHeader: "reader"}, http.StatusForbidden},
+		{"both credentials", http.MethodGet, map[string]string{
+			"Authorization": bearer(t, _userId),
+			apiKeyHeader:    "reader",
+		}, http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serve(apiKeyEngine(t), tc.method, "/", tc.header)
+			require.Equal(t, tc.want, w.Code, w.Body.String())
+
+			if tc.want == http.StatusUnauthorized {
+				require.Equal(t, _apiKeyChallenge, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestAPIKeyPrincipal(t *testing.T) {
+	t.Parallel()
+
+	w := serve(apiKeyEngine(t), http.MethodGet, "/", map[string]string{apiKeyHeader: "reader"})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"user_id": "", "roles": null, "api_key_id": "reader", "scopes": ["posts:read"]}`, w.Body.String())
+}
+
+func TestAPIKeyRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		e       = apiKeyEngine(t)
+		limited = map[string]string{apiKeyHeader: "limited"}
+	)
+
+	require.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/", limited).Code)
+
+	w := serve(e, http.MethodGet, "/", limited)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "60", w.Header().Get("Retry-After"))
+	require.Equal(t, "1;w=60", w.Header().Get("RateLimit-Policy"))
+
+	require.Equal(t, http.StatusOK, serve(e, http.MethodGet, "/", map[string]string{apiKeyHeader: "reader"}).Code,
+		"keys without a rate limit aren't held to one")
+}
+
+func TestAPIKeyFailures(t *testing.T) {
+	t.Parallel()
+
+	e := apiKeyEngine(t)
+
+	try := func(token, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set(apiKeyHeader, token)
+
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+
+		return w
+	}
+
+	for i := 0; i < _apiKeyFailures.Limit; i++ {
+		require.Equal(t, http.StatusUnauthorized, try("guess", "192.0.2.1").Code)
+	}
+
+	w := try("reader", "192.0.2.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code, "even a valid key is turned away")
+	require.Equal(t, "6", w.Header().Get("Retry-After"))
+
+	require.Equal(t, http.StatusOK, try("reader", "192.0.2.2").Code, "other clients are unaffected")
+}
+
+func TestActingUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+		user   string
+	}{
+		{"user", map[string]string{"Authorization": bearer(t, _userId)}, http.StatusOK, _userId},
+		{"key without posts:impersonate", map[string]string{apiKeyHeader: "writer"}, http.StatusForbidden, ""},
+		{"key with posts:impersonate", map[string]string{apiKeyHeader: "importer"}, http.StatusOK, "named"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serve(apiKeyEngine(t), http.MethodPost, "/?user_id=named", tc.header)
+			require.Equal(t, tc.want, w.Code, w.Body.String())
+
+			if tc.want == http.StatusOK {
+				require.Equal(t, tc.user, w.Body.String())
+			}
+		})
+	}
+}
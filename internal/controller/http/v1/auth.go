@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// _authChallenge is the WWW-Authenticate challenge of a 401 response.
+const _authChallenge = `Bearer realm="post-service"`
+
+// authenticate verifies the bearer token of a request, if it has one, and
+// puts its principal in the request context. Requests without a token are
+// left to requireAuth; a bad token is rejected right away.
+func authenticate(v *jwtauth.Verifier, l logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+
+			return
+		}
+
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			unauthorized(c, `error="invalid_request", error_description="expected a bearer token"`)
+
+			return
+		}
+
+		claims, err := v.Verify(strings.TrimSpace(token))
+		if err != nil {
+			l.Error(err, "http - v1 - authenticate")
+			unauthorized(c, `error="invalid_token"`)
+
+			return
+		}
+
+		ctx := entity.ContextWithPrincipal(c.Request.Context(), &entity.Principal{
+			UserId: claims.Subject,
+			Roles:  claims.Roles,
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// requireAuth rejects requests neither authenticate nor authenticateAPIKey
+// found a principal for.
+func requireAuth(c *gin.Context) {
+	if _, ok := entity.PrincipalFromContext(c.Request.Context()); !ok {
+		c.Header("WWW-Authenticate", _authChallenge)
+		c.Writer.Header().Add("WWW-Authenticate", _apiKeyChallenge)
+		errorResponse(c, http.StatusUnauthorized, "unauthorized")
+
+		return
+	}
+
+	c.Next()
+}
+
+// unauthorized responds 401 with a bearer challenge carrying params.
+func unauthorized(c *gin.Context, params string) {
+	challenge := _authChallenge
+	if params != "" {
+		challenge += ", " + params
+	}
+
+	c.Header("WWW-Authenticate", challenge)
+	errorResponse(c, http.StatusUnauthorized, "unauthorized")
+}
+
+// principal returns the caller of a route behind requireAuth.
+func principal(c *gin.Context) *entity.Principal {
+	p, _ := entity.PrincipalFromContext(c.Request.Context())
+
+	return p
+}
+
+// actingUser returns the user a request behind requireAuth acts as: the
+// authenticated user or, for API key callers, which have no user of their
+// own, the user they name. Only keys granted posts:impersonate may name one;
+// it responds 403 to other keys and returns false.
+func actingUser(c *gin.Context, named string) (string, bool) {
+	p := principal(c)
+	if p.UserId != "" {
+		return p.UserId, true
+	}
+
+	if !p.HasScope(entity.ScopePostsImpersonate) {
+		errorResponse(c, http.StatusForbidden, "api key lacks the "+entity.ScopePostsImpersonate+" scope")
+
+		return "", false
+	}
+
+	return named, true
+}
@@ -0,0 +1,234 @@
+package v1
+
+import (
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type commentRoutes struct {
+	t usecase.Comment
+	l logger.Interface
+}
+
+func newCommentRoutes(handler *gin.RouterGroup, t usecase.Comment, l logger.Interface, rl *rateLimiter) {
+	r := &commentRoutes{t, l}
+
+	h := handler.Group("/post/:id/comments", rl.limit(limitComments))
+	{
+		h.POST("", requireAuth, r.CreateComment)
+		h.GET("", r.ListComments)
+		h.GET("/:comment_id", r.GetComment)
+		h.PUT("/:comment_id", requireAuth, r.UpdateComment)
+		h.DELETE("/:comment_id", requireAuth, r.DeleteComment)
+	}
+}
+
+// Create Comment
+// @Router /post/{id}/comments [post]
+// @Summary create comment
+// @Tags Comment
+// @Description Comment on a post as the authenticated user, or as user_id for API key callers with the posts:impersonate scope, or reply to one of its comments with parent_id
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "post id"
+// @Param CommentDetails body entity.Comment true "Create comment"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
+// @Success 201 {object} entity.Comment
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 413 {object} response
+// @Failure 422 {object} entity.ValidationError
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (r *commentRoutes) CreateComment(c *gin.Context) {
+	var body entity.Comment
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		r.l.Error(err, "http - v1 - create comment")
+		errorResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	body.Id = uuid.New().String()
+	body.PostId = c.Param("id")
+	body.APIKeyId = principal(c).APIKeyId
+	userId, ok := actingUser(c, body.UserId)
+	if !ok {
+		return
+	}
+
+	body.UserId = userId
+
+	comment, err := r.t.CreateComment(c.Request.Context(), &body)
+	if err != nil {
+		r.l.Error(err, "http - v1 - create comment")
+		serviceErrorResponse(c, err, "create comment service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Get Comment
+// @Router /post/{id}/comments/{comment_id} [get]
+// @Summary get comment
+// @Tags Comment
+// @Description Get a comment of a post
+// @Produce json
+// @Param id path string true "post id"
+// @Param comment_id path string true "comment id"
+// @Success 201 {object} entity.Comment
+// @Failure 404 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (r *commentRoutes) GetComment(c *gin.Context) {
+	comment, err := r.t.GetComment(c.Request.Context(), c.Param("id"), c.Param("comment_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - get comment")
+		serviceErrorResponse(c, err, "get comment service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Update Comment
+// @Router /post/{id}/comments/{comment_id} [put]
+// @Summary update comment
+// @Tags Comment
+// @Description Change the content of a comment; authors can edit their own comments, moderators any comment and API keys the comments they wrote
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "post id"
+// @Param comment_id path string true "comment id"
+// @Param CommentInfo body entity.Comment true "Update comment"
+// @Success 201 {object} entity.Comment
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 422 {object} entity.ValidationError
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (r *commentRoutes) UpdateComment(c *gin.Context) {
+	var body entity.Comment
+
+	err := c.ShouldBindJSON(&body)
+	if err != nil {
+		r.l.Error(err, "http - v1 - update comment")
+		errorResponse(c, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	body.Id = c.Param("comment_id")
+	body.PostId = c.Param("id")
+
+	comment, err := r.t.UpdateComment(c.Request.Context(), &body)
+	if err != nil {
+		r.l.Error(err, "http - v1 - update comment")
+		serviceErrorResponse(c, err, "update comment service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// Delete Comment
+// @Router /post/{id}/comments/{comment_id} [delete]
+// @Summary delete comment
+// @Tags Comment
+// @Description Delete a comment together with all replies to it; only its author, the API key that wrote it, or an admin, can
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "post id"
+// @Param comment_id path string true "comment id"
+// @Success 201 {object} entity.MessageResponse
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (r *commentRoutes) DeleteComment(c *gin.Context) {
+	err := r.t.DeleteComment(c.Request.Context(), c.Param("id"), c.Param("comment_id"))
+	if err != nil {
+		r.l.Error(err, "http - v1 - delete comment")
+		serviceErrorResponse(c, err, "delete comment service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, entity.MessageResponse{Message: "comment was deleted successfully"})
+}
+
+// List Comments
+// @Router /post/{id}/comments [get]
+// @Summary list comments
+// @Tags Comment
+// @Description List the comments of a post oldest first, or only the replies to parent_id
+// @Produce json
+// @Param id path string true "post id"
+// @Param parent_id query string false "only direct replies to this comment"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(20)
+// @Success 201 {object} entity.Comments
+// @Failure 400 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (r *commentRoutes) ListComments(c *gin.Context) {
+	req := entity.CommentFilter{
+		PostId:   c.Param("id"),
+		ParentId: c.Query("parent_id"),
+	}
+
+	rawPage := c.DefaultQuery("page", "1")
+	page, err := strconv.Atoi(rawPage)
+	if err != nil || page < 1 {
+		r.l.Error(fmt.Errorf("parsing page %q: %w", rawPage, err), "http - v1 - list comments")
+		errorResponse(c, http.StatusBadRequest, "list comments page parse error")
+
+		return
+	}
+
+	rawLimit := c.DefaultQuery("limit", strconv.Itoa(_defaultLimit))
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 || limit > _maxLimit {
+		r.l.Error(fmt.Errorf("parsing limit %q: %w", rawLimit, err), "http - v1 - list comments")
+		errorResponse(c, http.StatusBadRequest, "list comments limit parse error")
+
+		return
+	}
+
+	req.Page = int64(page)
+	req.Limit = int64(limit)
+
+	comments, err := r.t.ListComments(c.Request.Context(), &req)
+	if err != nil {
+		r.l.Error(err, "http - v1 - list comments")
+		serviceErrorResponse(c, err, "list comments service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
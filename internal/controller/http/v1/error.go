@@ -1,6 +1,12 @@
 package v1
 
-import "github.com/gin-gonic/gin"
+import (
+	"errors"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
 
 type response struct {
 	Error string `json:"error" example:"message"`
@@ -9,3 +15,34 @@ type response struct {
 func errorResponse(c *gin.Context, code int, msg string) {
 	c.AbortWithStatusJSON(code, response{Error: msg})
 }
+
+// serviceErrorResponse maps a use case error to its HTTP status. Field-level
+// validation errors are rendered as {"errors":[...]}, other domain errors with
+// their own message; anything else is a 500 with msg, so driver details never
+// reach the client.
+func serviceErrorResponse(c *gin.Context, err error, msg string) {
+	var validationErr *entity.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, validationErr)
+	case errors.Is(err, entity.ErrNotFound):
+		errorResponse(c, http.StatusNotFound, entity.ErrNotFound.Error())
+	case errors.Is(err, entity.ErrVersionConflict):
+		// A stale If-Match is a failed precondition; a stale version in the
+		// body is a plain conflict.
+		code := http.StatusConflict
+		if c.GetHeader("If-Match") != "" {
+			code = http.StatusPreconditionFailed
+		}
+		errorResponse(c, code, entity.ErrVersionConflict.Error())
+	case errors.Is(err, entity.ErrConflict):
+		errorResponse(c, http.StatusConflict, entity.ErrConflict.Error())
+	case errors.Is(err, entity.ErrValidation):
+		errorResponse(c, http.StatusUnprocessableEntity, entity.ErrValidation.Error())
+	case errors.Is(err, entity.ErrForbidden):
+		errorResponse(c, http.StatusForbidden, entity.ErrForbidden.Error())
+	default:
+		errorResponse(c, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	validation := &entity.ValidationError{}
+	validation.Add("title", entity.CodeRequired)
+
+	tests := []struct {
+		name    string
+		err     error
+		ifMatch string
+		code    int
+		body    string
+	}{
+		{"not found", fmt.Errorf("repo: %w", entity.ErrNotFound), "", http.StatusNotFound, `{"error":"not found"}`},
+		{"version conflict", entity.ErrVersionConflict, "", http.StatusConflict, `{"error":"conflict: version mismatch"}`},
+		{"stale If-Match", entity.ErrVersionConflict, `"3"`, http.StatusPreconditionFailed, `{"error":"conflict: version mismatch"}`},
+		{"conflict", fmt.Errorf("%w: taken", entity.ErrConflict), "", http.StatusConflict, `{"error":"conflict"}`},
+		{"conflict with If-Match", entity.ErrInvalidTransition, `"3"`, http.StatusConflict, `{"error":"conflict"}`},
+		{"validation", fmt.Errorf("usecase: %w", entity.ErrValidation), "", http.StatusUnprocessableEntity, `{"error":"validation failed"}`},
+		{
+			"validation fields",
+			fmt.Errorf("usecase: %w", validation),
+			"",
+			http.StatusUnprocessableEntity,
+			`{"errors":[{"field":"title","code":"required"}]}`,
+		},
+		{"forbidden", entity.ErrForbidden, "", http.StatusForbidden, `{"error":"forbidden"}`},
+		{"other", errors.New("connection refused"), "", http.StatusInternalServerError, `{"error":"get post service problems"}`},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			serviceErrorResponse(c, tc.err, "get post service problems")
+
+			require.True(t, c.IsAborted())
+			require.Equal(t, tc.code, w.Code)
+			require.JSONEq(t, tc.body, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag formats a post version as a strong entity tag.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatchVersion returns the post version from the If-Match header, or 0
+// when the header is absent. Weak tags are accepted as well.
+func ifMatchVersion(c *gin.Context) (int64, error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(raw, "W/"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid If-Match %q", raw)
+	}
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match %q", raw)
+	}
+
+	return version, nil
+}
+
+// requireVersion returns the version a write is based on: If-Match when
+// present, fallback otherwise. Without either it answers 428. On error the
+// response has already been written.
+func requireVersion(c *gin.Context, fallback int64) (int64, error) {
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+
+		return 0, err
+	}
+
+	if version == 0 {
+		version = fallback
+	}
+
+	if version < 1 {
+		errorResponse(c, http.StatusPreconditionRequired, "If-Match header or version is required")
+
+		return 0, fmt.Errorf("no If-Match or version")
+	}
+
+	return version, nil
+}
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const _secret = "0123456789abcdef0123456789abcdef"
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	os.Exit(m.Run())
+}
+
+// nopLogger discards what it is given.
+type nopLogger struct{}
+
+func (nopLogger) Debug(interface{}, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})       {}
+func (nopLogger) Warn(string, ...interface{})       {}
+func (nopLogger) Error(interface{}, ...interface{}) {}
+func (nopLogger) Fatal(interface{}, ...interface{}) {}
+
+// fakeAPIKeys authenticates the keys it maps tokens to; it implements only
+// AuthenticateAPIKey of usecase.APIKey.
+type fakeAPIKeys map[string]*entity.APIKey
+
+var _ usecase.APIKey = fakeAPIKeys(nil)
+
+func (f fakeAPIKeys) AuthenticateAPIKey(_ context.Context, token string) (*entity.APIKey, error) {
+	if key, ok := f[token]; ok {
+		return key, nil
+	}
+
+	return nil, entity.ErrUnauthenticated
+}
+
+func (fakeAPIKeys) MintAPIKey(context.Context, *entity.APIKey) (*entity.APIKey, string, error) {
+	panic("not implemented")
+}
+
+func (fakeAPIKeys) RevokeAPIKey(context.Context, string) error { panic("not implemented") }
+
+func (fakeAPIKeys) ListAPIKeys(context.Context) (*entity.APIKeys, error) { panic("not implemented") }
+
+func (fakeAPIKeys) FlushAPIKeyUsage(context.Context) (int64, error) { panic("not implemented") }
+
+// fakeStore is a ratelimit.Store whose Take answers with the result set for
+// the group a key belongs to, or a bucket of rate with a token taken, and
+// records the keys taken from. Peek always finds a full bucket.
+type fakeStore struct {
+	mu      sync.Mutex
+	results map[string]ratelimit.Result
+	err     error
+	keys    []string
+}
+
+var _ ratelimit.Store = (*fakeStore)(nil)
+
+func (f *fakeStore) Take(_ context.Context, key string, rate ratelimit.Rate) (ratelimit.Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = append(f.keys, key)
+	if f.err != nil {
+		return ratelimit.Result{}, f.err
+	}
+
+	group, _, _ := strings.Cut(key, ":")
+	if res, ok := f.results[group]; ok {
+		return res, nil
+	}
+
+	return ratelimit.Result{Allowed: true, Limit: rate.Limit, Remaining: rate.Limit - 1, Reset: rate.Period}, nil
+}
+
+func (f *fakeStore) Peek(_ context.Context, _ string, rate ratelimit.Rate) (ratelimit.Result, error) {
+	return ratelimit.Result{Allowed: true, Limit: rate.Limit, Remaining: rate.Limit}, nil
+}
+
+func (f *fakeStore) taken() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.keys
+}
+
+// verifier returns a verifier of the tokens bearer signs.
+func verifier(t *testing.T) *jwtauth.Verifier {
+	t.Helper()
+
+	v, err := jwtauth.New(jwtauth.HMACSecret(_secret))
+	require.NoError(t, err)
+
+	return v
+}
+
+// bearer returns the Authorization header of user.
+func bearer(t *testing.T, user string, roles ...string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwtauth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: roles,
+	}).SignedString([]byte(_secret))
+	require.NoError(t, err)
+
+	return "Bearer " + token
+}
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parseSort parses a sort spec such as "-likes,created_at" into sort fields.
+// A leading "-" sorts that field descending; only entity.SortableFields are
+// accepted, each at most once.
+func parseSort(spec string) ([]entity.SortField, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	var (
+		keys = strings.Split(spec, ",")
+		sort = make([]entity.SortField, 0, len(keys))
+		seen = make(map[string]bool, len(keys))
+	)
+
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+
+		field := entity.SortField{Field: strings.TrimPrefix(key, "-")}
+		field.Desc = field.Field != key
+
+		if !entity.IsSortable(field.Field) {
+			return nil, fmt.Errorf("unknown sort field %q", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", field.Field)
+		}
+		seen[field.Field] = true
+
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
+
+// parseFilters reads the optional list filters from the query string.
+// List parameters may be repeated or comma separated.
+func parseFilters(c *gin.Context, req *entity.GetListFilter) error {
+	var err error
+
+	req.Categories = queryList(c, "category")
+
+	req.Ids = queryList(c, "ids")
+	for _, id := range req.Ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("invalid id %q", id)
+		}
+	}
+
+	req.TitlePrefix = c.Query("title_prefix")
+
+	if tags := queryList(c, "tags"); len(tags) > 0 {
+		req.TagsAny = entity.NormalizeTags(tags)
+	}
+	if tags := queryList(c, "tags_all"); len(tags) > 0 {
+		req.TagsAll = entity.NormalizeTags(tags)
+	}
+
+	for _, t := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &req.CreatedFrom},
+		{"created_to", &req.CreatedTo},
+		{"updated_from", &req.UpdatedFrom},
+		{"updated_to", &req.UpdatedTo},
+	} {
+		*t.dst, err = queryTime(c, t.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, n := range []struct {
+		name string
+		dst  **int64
+	}{
+		{"min_likes", &req.MinLikes},
+		{"min_views", &req.MinViews},
+	} {
+		*n.dst, err = queryInt(c, n.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queryList returns the non-empty values of a repeated or comma separated parameter.
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+
+	for _, raw := range c.QueryArray(name) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return values
+}
+
+func queryTime(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected RFC 3339 time", name)
+	}
+
+	t = t.UTC()
+
+	return &t, nil
+}
+
+func queryInt(c *gin.Context, name string) (*int64, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s, expected a non-negative integer", name)
+	}
+
+	return &n, nil
+}
@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec string
+		want []entity.SortField
+		err  string
+	}{
+		{"", nil, ""},
+		{"likes", []entity.SortField{{Field: "likes"}}, ""},
+		{"-likes", []entity.SortField{{Field: "likes", Desc: true}}, ""},
+		{
+			"-views,created_at, -title ",
+			[]entity.SortField{{Field: "views", Desc: true}, {Field: "created_at"}, {Field: "title", Desc: true}},
+			"",
+		},
+		{"likes,comments", nil, `unknown sort field "comments"`},
+		{"id", nil, `unknown sort field "id"`},
+		{"likes,", nil, `unknown sort field ""`},
+		{"--likes", nil, `unknown sort field "-likes"`},
+		{"likes,-likes", nil, `duplicate sort field "likes"`},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.spec, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseSort(tc.spec)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	t.Parallel()
+
+	var (
+		id1   = "d0b69f3b-2021-4d91-8e13-c243d9eb5292"
+		id2   = "0f4b1a8e-6b0a-4c55-9a3b-5e0f2b6c7d11"
+		from  = time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+		to    = time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+		zero  = int64(0)
+		likes = int64(10)
+	)
+
+	tests := []struct {
+		name  string
+		query string
+		want  entity.GetListFilter
+		err   string
+	}{
+		{"none", "", entity.GetListFilter{}, ""},
+		{
+			"repeated and comma separated lists",
+			"category=Nature,%20Tech&category=&category=Art&ids=" + id1 + "&ids=," + id2 + "&title_prefix=Go%20",
+			entity.GetListFilter{Categories: []string{"Nature", "Tech", "Art"}, Ids: []string{id1, id2}, TitlePrefix: "Go "},
+			"",
+		},
+		{
+			"normalized tags",
+			"tags=SQL,Go&tags=go&tags_all=Machine%20Learning,,%23",
+			entity.GetListFilter{TagsAny: []string{"go", "sql"}, TagsAll: []string{"machine-learning"}},
+			"",
+		},
+		{
+			"times in UTC",
+			"created_from=2024-03-01T10:00:00%2B03:00&created_to=2024-03-02T00:00:00Z" +
+				"&updated_from=2024-03-01T07:00:00Z&updated_to=2024-03-01T19:00:00-05:00",
+			entity.GetListFilter{CreatedFrom: &from, CreatedTo: &to, UpdatedFrom: &from, UpdatedTo: &to},
+			"",
+		},
+		{"counts", "min_likes=10&min_views=0", entity.GetListFilter{MinLikes: &likes, MinViews: &zero}, ""},
+		{"invalid id", "ids=" + id1 + ",42", entity.GetListFilter{}, `invalid id "42"`},
+		{"invalid time", "updated_to=2024-03-02", entity.GetListFilter{}, "invalid updated_to, expected RFC 3339 time"},
+		{"negative count", "min_views=-1", entity.GetListFilter{}, "invalid min_views, expected a non-negative integer"},
+		{"invalid count", "min_likes=ten", entity.GetListFilter{}, "invalid min_likes, expected a non-negative integer"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/posts?"+tc.query, nil)
+
+			var got entity.GetListFilter
+
+			err := parseFilters(c, &got)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fourth-exam/post-service-clean-arch/pkg/idempotency"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	replayedHeader    = "Idempotent-Replayed"
+
+	_maxIdempotencyKeyLength = 255
+	// _maxIdempotentBodySize bounds the body of a request with a key, which
+	// is read whole to fingerprint it.
+	_maxIdempotentBodySize = 1 << 20
+)
+
+// _replayedHeaders are the response headers replayed besides the body.
+var _replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// idempotent makes POST requests carrying an Idempotency-Key safe to retry:
+// the first request with a key runs and its response is stored, retries get
+// that response replayed. A key is the client's own, reusing it for another
+// request is a 422 and retrying while the first request runs a 409. Server
+// errors aren't stored, so those requests can be retried for real.
+func idempotent(store idempotency.Store, l logger.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+
+			return
+		}
+
+		if len(key) > _maxIdempotencyKeyLength {
+			errorResponse(c, http.StatusBadRequest, "idempotency key too long")
+
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, _maxIdempotentBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				errorResponse(c, http.StatusRequestEntityTooLarge, "request body too large")
+
+				return
+			}
+
+			l.Error(err, "http - v1 - idempotent")
+			errorResponse(c, http.StatusBadRequest, "invalid request body")
+
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var (
+			ctx         = context.WithoutCancel(c.Request.Context())
+			scoped      = client(c) + ":" + key
+			fingerprint = idempotency.Fingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+		)
+
+		rec, err := store.Begin(ctx, scoped, fingerprint)
+		if err != nil {
+			l.Error(err, "http - v1 - idempotent - store.Begin")
+			errorResponse(c, http.StatusInternalServerError, "idempotency service problems")
+
+			return
+		}
+
+		switch {
+		case rec == nil:
+		case !bytes.Equal(rec.Fingerprint, fingerprint):
+			errorResponse(c, http.StatusUnprocessableEntity, "idempotency key reused for another request")
+
+			return
+		case rec.Response == nil:
+			c.Header("Retry-After", "1")
+			errorResponse(c, http.StatusConflict, "a request with this idempotency key is in progress")
+
+			return
+		default:
+			for name, values := range rec.Response.Header {
+				c.Writer.Header()[name] = values
+			}
+			c.Header(replayedHeader, "true")
+			c.AbortWithStatus(rec.Response.Status)
+
+			if _, err := c.Writer.Write(rec.Response.Body); err != nil {
+				l.Error(err, "http - v1 - idempotent - replay")
+			}
+
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+
+		defer func() {
+			if p := recover(); p != nil {
+				release(ctx, store, scoped, l)
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			release(ctx, store, scoped, l)
+
+			return
+		}
+
+		res := &idempotency.Response{Status: status, Header: http.Header{}, Body: w.body.Bytes()}
+		for _, name := range _replayedHeaders {
+			if values := c.Writer.Header().Values(name); len(values) > 0 {
+				res.Header[name] = values
+			}
+		}
+
+		if err := store.Complete(ctx, scoped, res); err != nil {
+			l.Error(err, "http - v1 - idempotent - store.Complete")
+		}
+	}
+}
+
+// release drops the claim on key, logging failures: the claim lapses anyway.
+func release(ctx context.Context, store idempotency.Store, key string, l logger.Interface) {
+	if err := store.Release(ctx, key); err != nil {
+		l.Error(err, "http - v1 - idempotent - store.Release")
+	}
+}
+
+// recordingWriter keeps a copy of the body written to the client.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+
+	return w.ResponseWriter.WriteString(s)
+}
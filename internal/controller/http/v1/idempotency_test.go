@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"fourth-exam/post-service-clean-arch/pkg/idempotency"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// idempotencyEngine serves POST / with the number of the run and the body
+// it was given, with the status of the status query parameter, 201 by
+// default. Runs of POST /block wait for release once started is signalled.
+type idempotencyEngine struct {
+	*gin.Engine
+
+	runs    atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newIdempotencyEngine() *idempotencyEngine {
+	e := &idempotencyEngine{
+		Engine:  gin.New(),
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	run := func(c *gin.Context) {
+		n := e.runs.Add(1)
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		status := http.StatusCreated
+		if s := c.Query("status"); s != "" {
+			status, _ = strconv.Atoi(s)
+		}
+
+		c.Header("Location", "/runs/"+strconv.Itoa(int(n)))
+		c.Header("X-Run", strconv.Itoa(int(n)))
+		c.JSON(status, gin.H{"run": n, "body": string(body)})
+	}
+
+	h := e.Group("/", idempotent(idempotency.NewMemory(), nopLogger{}))
+	h.POST("/", run)
+	h.GET("/", run)
+	h.POST("/block", func(c *gin.Context) {
+		e.started <- struct{}{}
+		<-e.release
+		run(c)
+	})
+
+	return e
+}
+
+func (e *idempotencyEngine) send(method, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotencyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	t.Parallel()
+
+	e := newIdempotencyEngine()
+
+	first := e.send(http.MethodPost, "/", "k1", `{"a":1}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get(replayedHeader))
+
+	replay := e.send(http.MethodPost, "/", "k1", `{"a":1}`)
+	require.Equal(t, http.StatusCreated, replay.Code)
+	require.Equal(t, "true", replay.Header().Get(replayedHeader))
+	require.Equal(t, first.Body.String(), replay.Body.String())
+	require.Equal(t, "/runs/1", replay.Header().Get("Location"))
+	require.Equal(t, first.Header().Get("Content-Type"), replay.Header().Get("Content-Type"))
+	require.Empty(t, replay.Header().Get("X-Run"), "only some headers are replayed")
+	require.Equal(t, int32(1), e.runs.Load())
+
+	require.Equal(t, http.StatusCreated, e.send(http.MethodPost, "/", "k2", `{"a":1}`).Code)
+	require.Equal(t, int32(2), e.runs.Load(), "another key runs again")
+}
+
+func TestIdempotentPassThrough(t *testing.T) {
+	t.Parallel()
+
+	e := newIdempotencyEngine()
+
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusCreated, e.send(http.MethodPost, "/", "", `{}`).Code)
+		require.Equal(t, http.StatusCreated, e.send(http.MethodGet, "/", "k", "").Code)
+	}
+	require.Equal(t, int32(4), e.runs.Load(), "requests without a key, or not POST, always run")
+}
+
+func TestIdempotentKeyReused(t *testing.T) {
+	t.Parallel()
+
+	e := newIdempotencyEngine()
+
+	require.Equal(t, http.StatusCreated, e.send(http.MethodPost, "/", "k", `{"a":1}`).Code)
+
+	for _, req := range []struct{ target, body string }{
+		{"/", `{"a":2}`},
+		{"/?status=201", `{"a":1}`},
+	} {
+		w := e.send(http.MethodPost, req.target, "k", req.body)
+		require.Equal(t, http.StatusUnprocessableEntity, w.Code, req)
+		require.JSONEq(t, `{"error": "idempotency key reused for another request"}`, w.Body.String())
+	}
+	require.Equal(t, int32(1), e.runs.Load())
+}
+
+func TestIdempotentInFlight(t *testing.T) {
+	t.Parallel()
+
+	e := newIdempotencyEngine()
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() { done <- e.send(http.MethodPost, "/block", "k", `{}`) }()
+	<-e.started
+
+	w := e.send(http.MethodPost, "/block", "k", `{}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	close(e.release)
+	require.Equal(t, http.StatusCreated, (<-done).Code)
+
+	require.Equal(t, "true", e.send(http.MethodPost, "/block", "k", `{}`).Header().Get(replayedHeader))
+}
+
+func TestIdempotentRelease(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		status := status
+
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				e      = newIdempotencyEngine()
+				target = "/?status=" + strconv.Itoa(status)
+			)
+
+			for i := 0; i < 2; i++ {
+				w := e.send(http.MethodPost, target, "k", `{}`)
+				require.Equal(t, status, w.Code)
+				require.Empty(t, w.Header().Get(replayedHeader))
+			}
+			require.Equal(t, int32(2), e.runs.Load(), "the request is retried for real")
+		})
+	}
+
+	e := newIdempotencyEngine()
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusBadRequest, e.send(http.MethodPost, "/?status=400", "k", `{}`).Code)
+	}
+	require.Equal(t, int32(1), e.runs.Load(), "client errors are replayed")
+}
+
+func TestIdempotentRejects(t *testing.T) {
+	t.Parallel()
+
+	e := newIdempotencyEngine()
+
+	w := e.send(http.MethodPost, "/", strings.Repeat("k", _maxIdempotencyKeyLength+1), `{}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = e.send(http.MethodPost, "/", "k", strings.Repeat("a", _maxIdempotentBodySize+1))
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	require.Zero(t, e.runs.Load())
+
+	w = e.send(http.MethodPost, "/", "k", strings.Repeat("a", _maxIdempotentBodySize))
+	require.Equal(t, http.StatusCreated, w.Code, "the key is free after a rejected request")
+}
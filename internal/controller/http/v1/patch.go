@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"sort"
+	"strings"
+)
+
+// Patch document media types.
+const (
+	_mergePatchType = "application/merge-patch+json"
+	_jsonPatchType  = "application/json-patch+json"
+)
+
+// readOnlyFields are post fields maintained by the service.
+var readOnlyFields = map[string]bool{
+	"id": true, "user_id": true, "likes": true, "dislikes": true, "views": true,
+	"comments_count": true, "status": true, "published_at": true,
+	"created_at": true, "updated_at": true, "deleted_at": true,
+}
+
+// parseMergePatch turns an RFC 7396 merge patch into a post patch. A member
+// set to null removes the field, which for posts leaves it empty and fails
+// validation, except for tags where it clears them.
+func parseMergePatch(body []byte) (*entity.PostPatch, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("merge patch must be a JSON object: %w", err)
+	}
+
+	var (
+		patch = &entity.PostPatch{}
+		v     = &entity.ValidationError{}
+	)
+
+	fields := make([]string, 0, len(doc))
+	for field := range doc {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if err := applyPatchValue(patch, v, field, doc[field]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// parseJSONPatch turns an RFC 6902 JSON Patch into a post patch. Only the
+// top-level fields can be targeted: add, replace and remove set them, and
+// test on /version states the version the patch is based on.
+func parseJSONPatch(body []byte) (*entity.PostPatch, error) {
+	var ops []jsonPatchOp
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return nil, fmt.Errorf("json patch must be an array of operations: %w", err)
+	}
+
+	var (
+		patch = &entity.PostPatch{}
+		v     = &entity.ValidationError{}
+	)
+
+	for _, op := range ops {
+		field := strings.TrimPrefix(op.Path, "/")
+		if field == op.Path || strings.Contains(field, "/") {
+			return nil, fmt.Errorf("unsupported path %q", op.Path)
+		}
+
+		switch op.Op {
+		case "add", "replace":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%s %q needs a value", op.Op, op.Path)
+			}
+			if field == "version" {
+				v.Add(field, entity.CodeReadOnly)
+
+				continue
+			}
+		case "remove":
+			op.Value = json.RawMessage("null")
+		case "test":
+			if field != "version" {
+				return nil, fmt.Errorf("test is only supported on /version")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported op %q", op.Op)
+		}
+
+		if err := applyPatchValue(patch, v, field, op.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// applyPatchValue sets field of patch from its raw JSON value, recording
+// read-only and unknown fields in v.
+func applyPatchValue(patch *entity.PostPatch, v *entity.ValidationError, field string, raw json.RawMessage) error {
+	var dst **string
+
+	switch field {
+	case "title":
+		dst = &patch.Title
+	case "content":
+		dst = &patch.Content
+	case "category":
+		dst = &patch.Category
+	case "tags":
+		var tags []string
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			return fmt.Errorf("tags must be an array of strings or null: %w", err)
+		}
+
+		patch.Tags = &tags
+
+		return nil
+	case "version":
+		if err := json.Unmarshal(raw, &patch.Version); err != nil {
+			return fmt.Errorf("version must be an integer: %w", err)
+		}
+
+		return nil
+	default:
+		if readOnlyFields[field] {
+			v.Add(field, entity.CodeReadOnly)
+		} else {
+			v.Add(field, entity.CodeUnknownField)
+		}
+
+		return nil
+	}
+
+	var value *string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("%s must be a string or null: %w", field, err)
+	}
+	if value == nil {
+		value = new(string)
+	}
+
+	*dst = value
+
+	return nil
+}
@@ -1,49 +1,79 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
 	"fourth-exam/post-service-clean-arch/internal/entity"
 	"fourth-exam/post-service-clean-arch/internal/usecase"
 	"fourth-exam/post-service-clean-arch/pkg/logger"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+const (
+	_defaultLimit = 20
+	_maxLimit     = 100
+)
+
 type postRoutes struct {
 	t usecase.Post
 	l logger.Interface
 }
 
-func newPostRoutes(handler *gin.RouterGroup, t usecase.Post, l logger.Interface) {
+func newPostRoutes(handler *gin.RouterGroup, t usecase.Post, l logger.Interface, rl *rateLimiter) {
 	r := &postRoutes{t, l}
 
-	h := handler.Group("/post")
+	h := handler.Group("/post", rl.limit(limitPosts))
 	{
-		h.POST("/create", r.CreatePost)
-		h.PUT("/update/:id", r.UpdatePost)
+		h.POST("/create", requireAuth, r.CreatePost)
+		h.PUT("/update/:id", requireAuth, r.UpdatePost)
+		h.PATCH("/:id", requireAuth, r.PatchPost)
 		h.GET("/:id", r.GetPostById)
-		h.PUT("/like", r.LikePost)
-		h.PUT("/dislike", r.DislikePost)
-		h.DELETE("/delete/:id", r.DeletePost)
+		h.PUT("/like", requireAuth, rl.limit(limitReact), r.LikePost)
+		h.PUT("/dislike", requireAuth, rl.limit(limitReact), r.DislikePost)
+		h.PUT("/unreact", requireAuth, rl.limit(limitReact), r.UnreactPost)
+		h.DELETE("/delete/:id", requireAuth, r.DeletePost)
+		h.POST("/:id/restore", requireAuth, r.RestorePost)
+		h.POST("/:id/publish", requireAuth, r.PublishPost)
+		h.POST("/:id/unpublish", requireAuth, r.UnpublishPost)
+		h.POST("/:id/archive", requireAuth, r.ArchivePost)
+		h.GET("/:id/revisions", r.ListRevisions)
+		h.GET("/:id/revisions/diff", r.DiffRevisions)
+		h.GET("/:id/revisions/:version", r.GetRevision)
+		h.POST("/:id/revisions/:version/revert", requireAuth, r.RevertPost)
 	}
 
+	handler.GET("/posts", r.ListPostsByCursor)
+	handler.GET("/posts/search", r.SearchPosts)
 	handler.GET("/posts/:page/:limit/:user_id", r.ListPostsByUserId)
 	handler.GET("/posts/:page/:limit", r.ListPosts)
+	handler.GET("/tags", r.ListTags)
 }
 
 // CreatePost
 // @Router /post/create [post]
 // @Summary create post
 // @Tags Post
-// @Description Insert a new post with provided details
+// @Description Insert a new post with provided details, authored by the authenticated user, or by user_id for API key callers with the posts:impersonate scope
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param PostDetails body entity.Post true "Create post"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
 // @Success 201 {object} entity.Post
 // @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 409 {object} response
+// @Failure 413 {object} response
+// @Failure 422 {object} entity.ValidationError
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) CreatePost(c *gin.Context) {
 	var (
@@ -61,11 +91,18 @@ func (p *postRoutes) CreatePost(c *gin.Context) {
 	}
 
 	body.Id = uuid.New().String()
+	body.APIKeyId = principal(c).APIKeyId
+	userId, ok := actingUser(c, body.UserId)
+	if !ok {
+		return
+	}
+
+	body.UserId = userId
 
 	post, err := p.t.CreatePost(c.Request.Context(), &body)
 	if err != nil {
 		p.l.Error(err, "http - v1 - create post")
-		errorResponse(c, http.StatusInternalServerError, "create post service problems")
+		serviceErrorResponse(c, err, "create post service problems")
 
 		return
 	}
@@ -77,13 +114,25 @@ func (p *postRoutes) CreatePost(c *gin.Context) {
 // @Router /post/update/{id} [put]
 // @Summary update post
 // @Tags Post
-// @Description Update post
+// @Description Update post; authors can edit their own posts, moderators any post and API keys the posts they wrote
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag of the post being edited; alternative to version in the body"
 // @Param PostInfo body entity.Post true "Update Post"
 // @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version"
 // @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 412 {object} response
+// @Failure 422 {object} entity.ValidationError
+// @Failure 428 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) UpdatePost(c *gin.Context) {
 	var (
@@ -102,119 +151,225 @@ func (p *postRoutes) UpdatePost(c *gin.Context) {
 	}
 
 	body.Id = id
+	body.Version, err = requireVersion(c, body.Version)
+	if err != nil {
+		p.l.Error(err, "http - v1 - update post")
+
+		return
+	}
+
 	response, err := p.t.UpdatePost(c.Request.Context(), &body)
 	if err != nil {
 		p.l.Error(err, "http - v1 - update post")
-		errorResponse(c, http.StatusInternalServerError, "update post service problems")
+		serviceErrorResponse(c, err, "update post service problems")
 
 		return
 	}
 
+	c.Header("ETag", etag(response.Version))
 	c.JSON(http.StatusOK, response)
 }
 
-// Like Post
-// @Router /post/like [put]
-// @Summary like post
+// Patch Post
+// @Router /post/{id} [patch]
+// @Summary patch post
 // @Tags Post
-// @Description Like post
+// @Description Update only the given fields: RFC 7396 merge patch (application/merge-patch+json, or application/json),
+// @Description or RFC 6902 JSON Patch (application/json-patch+json) with add/replace/remove on /title, /content, /category, /tags and test on /version
 // @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
 // @Produce json
-// @Param post_id body entity.PostRequest true "Like Post"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "id"
+// @Param If-Match header string false "ETag of the post being edited; alternative to version in the patch"
+// @Param PostPatch body entity.PostPatch true "Patch Post"
 // @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version"
 // @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 412 {object} response
+// @Failure 415 {object} response
+// @Failure 422 {object} entity.ValidationError
+// @Failure 428 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
-func (p *postRoutes) LikePost(c *gin.Context) {
-	var (
-		body        entity.PostRequest
-		jspbMarshal protojson.MarshalOptions
-	)
+func (p *postRoutes) PatchPost(c *gin.Context) {
+	id := c.Param("id")
 
-	jspbMarshal.UseProtoNames = true
-	err := c.ShouldBindJSON(&body)
+	body, err := c.GetRawData()
 	if err != nil {
-		p.l.Error(err, "http - v1 - like post")
+		p.l.Error(err, "http - v1 - patch post")
 		errorResponse(c, http.StatusBadRequest, "invalid request body")
 
 		return
 	}
 
-	post, err := p.t.GetPost(c.Request.Context(), body.PostId)
+	var patch *entity.PostPatch
+
+	switch c.ContentType() {
+	case _mergePatchType, gin.MIMEJSON:
+		patch, err = parseMergePatch(body)
+	case _jsonPatchType:
+		patch, err = parseJSONPatch(body)
+	default:
+		p.l.Error(fmt.Errorf("unsupported content type %q", c.ContentType()), "http - v1 - patch post")
+		errorResponse(c, http.StatusUnsupportedMediaType, "expected "+_mergePatchType+" or "+_jsonPatchType)
+
+		return
+	}
 	if err != nil {
-		p.l.Error(err, "http - v1 - get post")
-		errorResponse(c, http.StatusInternalServerError, "get post service problems")
+		p.l.Error(err, "http - v1 - patch post")
+		if errors.Is(err, entity.ErrValidation) {
+			serviceErrorResponse(c, err, "patch post service problems")
+		} else {
+			errorResponse(c, http.StatusBadRequest, err.Error())
+		}
 
 		return
 	}
-	post.Likes += 1
 
-	response, err := p.t.UpdatePost(c.Request.Context(), post)
+	patch.Version, err = requireVersion(c, patch.Version)
 	if err != nil {
-		p.l.Error(err, "http - v1 - update post")
-		errorResponse(c, http.StatusInternalServerError, "update post service problems")
+		p.l.Error(err, "http - v1 - patch post")
 
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	post, err := p.t.PatchPost(c.Request.Context(), id, patch)
+	if err != nil {
+		p.l.Error(err, "http - v1 - patch post")
+		serviceErrorResponse(c, err, "patch post service problems")
+
+		return
+	}
+
+	c.Header("ETag", etag(post.Version))
+	c.JSON(http.StatusOK, post)
+}
+
+// Like Post
+// @Router /post/like [put]
+// @Summary like post
+// @Tags Post
+// @Description Like post as the authenticated user, or as user_id for API key callers with the posts:impersonate scope, replacing a previous dislike
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param post_id body entity.PostRequest true "Like Post"
+// @Success 201 {object} entity.Post
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 422 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) LikePost(c *gin.Context) {
+	p.react(c, entity.ReactionLike, "like post")
 }
 
 // DisLike Post
 // @Router /post/dislike [put]
 // @Summary dislike post
 // @Tags Post
-// @Description Dislike post
+// @Description Dislike post as the authenticated user, or as user_id for API key callers with the posts:impersonate scope, replacing a previous like
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param post_id body entity.PostRequest true "Dislike Post"
 // @Success 201 {object} entity.Post
 // @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 422 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) DislikePost(c *gin.Context) {
-	var (
-		body        entity.PostRequest
-		jspbMarshal protojson.MarshalOptions
-	)
+	p.react(c, entity.ReactionDislike, "dislike post")
+}
+
+// Unreact Post
+// @Router /post/unreact [put]
+// @Summary remove reaction
+// @Tags Post
+// @Description Remove the like or dislike of the authenticated user, or of user_id for API key callers with the posts:impersonate scope, from post
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param post_id body entity.PostRequest true "Unreact Post"
+// @Success 201 {object} entity.Post
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 422 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) UnreactPost(c *gin.Context) {
+	p.react(c, "", "unreact post")
+}
+
+// react applies reaction ("" removes it) for the acting user.
+func (p *postRoutes) react(c *gin.Context, reaction, op string) {
+	var body entity.PostRequest
 
-	jspbMarshal.UseProtoNames = true
 	err := c.ShouldBindJSON(&body)
-	if err != nil {
-		p.l.Error(err, "http - v1 - dislike post")
+	if err != nil || body.PostId == "" {
+		p.l.Error(err, "http - v1 - "+op)
 		errorResponse(c, http.StatusBadRequest, "invalid request body")
 
 		return
 	}
 
-	post, err := p.t.GetPost(c.Request.Context(), body.PostId)
-	if err != nil {
-		p.l.Error(err, "http - v1 - get post")
-		errorResponse(c, http.StatusInternalServerError, "get post service problems")
-
+	userId, ok := actingUser(c, body.UserId)
+	if !ok {
 		return
 	}
-	post.Likes -= 1
 
-	response, err := p.t.UpdatePost(c.Request.Context(), post)
+	req := &entity.Reaction{
+		PostId:   body.PostId,
+		UserId:   userId,
+		Reaction: reaction,
+	}
+
+	var post *entity.Post
+	if reaction == "" {
+		post, err = p.t.Unreact(c.Request.Context(), req)
+	} else {
+		post, err = p.t.React(c.Request.Context(), req)
+	}
 	if err != nil {
-		p.l.Error(err, "http - v1 - update post")
-		errorResponse(c, http.StatusInternalServerError, "update post service problems")
+		p.l.Error(err, "http - v1 - "+op)
+		serviceErrorResponse(c, err, op+" service problems")
 
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, post)
 }
 
 // Get Post By Id
 // @Router /post/{id} [get]
 // @Summary get post by id
 // @Tags Post
-// @Description Get post
+// @Description Get post, counting a view of it once per client within the view window. Posts that aren't published are only found by their authors, moderators and admins
 // @Accept json
 // @Produce json
 // @Param id path string true "Id"
 // @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version, to send back as If-Match"
 // @Failure 400 {object} response
+// @Failure 404 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) GetPostById(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
@@ -222,14 +377,15 @@ func (p *postRoutes) GetPostById(c *gin.Context) {
 
 	id := c.Param("id")
 
-	post, err := p.t.GetPost(c.Request.Context(), id)
+	post, err := p.t.ViewPost(c.Request.Context(), id, c.ClientIP())
 	if err != nil {
 		p.l.Error(err, "http - v1 - get post")
-		errorResponse(c, http.StatusInternalServerError, "get post service problems")
+		serviceErrorResponse(c, err, "get post service problems")
 
 		return
 	}
 
+	c.Header("ETag", etag(post.Version))
 	c.JSON(http.StatusOK, post)
 }
 
@@ -237,12 +393,23 @@ func (p *postRoutes) GetPostById(c *gin.Context) {
 // @Router /post/delete/{id} [delete]
 // @Summary delete post
 // @Tags Post
-// @Description Delete post
+// @Description Delete post; only its author, the API key that wrote it, or an admin, can
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "id"
+// @Param If-Match header string false "ETag of the post being deleted"
+// @Param version query int false "version of the post being deleted; alternative to If-Match"
 // @Success 201 {object} entity.MessageResponse
 // @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 412 {object} response
+// @Failure 428 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) DeletePost(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
@@ -250,10 +417,30 @@ func (p *postRoutes) DeletePost(c *gin.Context) {
 
 	id := c.Param("id")
 
-	err := p.t.DeletePost(c.Request.Context(), id)
+	var queryVersion int64
+	if raw := c.Query("version"); raw != "" {
+		var err error
+
+		queryVersion, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			p.l.Error(err, "http - v1 - delete post - parsing version")
+			errorResponse(c, http.StatusBadRequest, "invalid version")
+
+			return
+		}
+	}
+
+	version, err := requireVersion(c, queryVersion)
+	if err != nil {
+		p.l.Error(err, "http - v1 - delete post")
+
+		return
+	}
+
+	err = p.t.DeletePost(c.Request.Context(), id, version)
 	if err != nil {
 		p.l.Error(err, "http - v1 - delete post")
-		errorResponse(c, http.StatusInternalServerError, "delete post service problems")
+		serviceErrorResponse(c, err, "delete post service problems")
 
 		return
 	}
@@ -263,6 +450,155 @@ func (p *postRoutes) DeletePost(c *gin.Context) {
 	})
 }
 
+// Restore Post
+// @Router /post/{id}/restore [post]
+// @Summary restore post
+// @Tags Post
+// @Description Restore a soft-deleted post (admin only)
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "id"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
+// @Success 201 {object} entity.Post
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 413 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) RestorePost(c *gin.Context) {
+	id := c.Param("id")
+
+	post, err := p.t.RestorePost(c.Request.Context(), id)
+	if err != nil {
+		p.l.Error(err, "http - v1 - restore post")
+		serviceErrorResponse(c, err, "restore post service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// Publish Post
+// @Router /post/{id}/publish [post]
+// @Summary publish post
+// @Tags Post
+// @Description Publish a draft or scheduled post now, or schedule it when publish_at is in the future
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "id"
+// @Param PublishRequest body entity.PublishRequest false "Publish time"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
+// @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version"
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 413 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) PublishPost(c *gin.Context) {
+	var body entity.PublishRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			p.l.Error(err, "http - v1 - publish post")
+			errorResponse(c, http.StatusBadRequest, "invalid request body")
+
+			return
+		}
+	}
+
+	var at time.Time
+	if body.PublishAt != nil {
+		at = body.PublishAt.UTC()
+	}
+
+	post, err := p.t.PublishPost(c.Request.Context(), c.Param("id"), at)
+	if err != nil {
+		p.l.Error(err, "http - v1 - publish post")
+		serviceErrorResponse(c, err, "publish post service problems")
+
+		return
+	}
+
+	c.Header("ETag", etag(post.Version))
+	c.JSON(http.StatusOK, post)
+}
+
+// Unpublish Post
+// @Router /post/{id}/unpublish [post]
+// @Summary unpublish post
+// @Tags Post
+// @Description Take a published, scheduled or archived post back to draft
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "id"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
+// @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version"
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 413 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) UnpublishPost(c *gin.Context) {
+	post, err := p.t.UnpublishPost(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		p.l.Error(err, "http - v1 - unpublish post")
+		serviceErrorResponse(c, err, "unpublish post service problems")
+
+		return
+	}
+
+	c.Header("ETag", etag(post.Version))
+	c.JSON(http.StatusOK, post)
+}
+
+// Archive Post
+// @Router /post/{id}/archive [post]
+// @Summary archive post
+// @Tags Post
+// @Description Take a post out of the listings for good; unpublish brings it back as a draft
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "id"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
+// @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version"
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 413 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) ArchivePost(c *gin.Context) {
+	post, err := p.t.ArchivePost(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		p.l.Error(err, "http - v1 - archive post")
+		serviceErrorResponse(c, err, "archive post service problems")
+
+		return
+	}
+
+	c.Header("ETag", etag(post.Version))
+	c.JSON(http.StatusOK, post)
+}
+
 // Get All Posts
 // @Router /posts/{page}/{limit} [get]
 // @Summary get all posts
@@ -271,9 +607,25 @@ func (p *postRoutes) DeletePost(c *gin.Context) {
 // @Accept json
 // @Param page path string true "page"
 // @Param limit path string true "limit"
-// @Param orderBy query string false "orderBy" Enums(content, title, category) "Order by"
+// @Param sort query string false "comma separated fields, - for descending: likes, views, created_at, updated_at, title" example(-likes,created_at)
+// @Param category query []string false "only these categories" collectionFormat(csv)
+// @Param ids query []string false "only these post ids" collectionFormat(csv)
+// @Param title_prefix query string false "title starts with (case-insensitive)"
+// @Param created_from query string false "created at or after (RFC 3339)"
+// @Param created_to query string false "created before (RFC 3339)"
+// @Param updated_from query string false "updated at or after (RFC 3339)"
+// @Param updated_to query string false "updated before (RFC 3339)"
+// @Param min_likes query int false "at least this many likes"
+// @Param min_views query int false "at least this many views"
+// @Param tags query []string false "tagged with any of these tags" collectionFormat(csv)
+// @Param tags_all query []string false "tagged with all of these tags" collectionFormat(csv)
+// @Param include_deleted query bool false "include soft-deleted posts (admin only)"
+// @Param status query []string false "statuses other than published are for moderators, admins and authors listing their own posts" collectionFormat(csv) default(published)
+// @Param with_total query bool false "count all matching posts" default(true)
 // @Success 201 {object} entity.Posts
 // @Failure 400 {object} response
+// @Failure 403 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) ListPosts(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
@@ -282,38 +634,18 @@ func (p *postRoutes) ListPosts(c *gin.Context) {
 	var (
 		req entity.GetListFilter
 	)
-	orderBy := c.Query("orderBy")
-	if orderBy != "" {
-		req.OrderBy = orderBy
-	} else {
-		req.OrderBy = "created_at"
-	}
-
-	page := c.Param("page")
-	pageToInt, err := strconv.Atoi(page)
-	if err != nil {
-		p.l.Error(err, "http - v1 - list posts - parsing page to int")
-		errorResponse(c, http.StatusInternalServerError, "list posts page parse error")
 
-		return
-	}
-
-	limit := c.Param("limit")
-	LimitToInt, err := strconv.Atoi(limit)
+	err := p.parseListFilter(c, &req)
 	if err != nil {
-		p.l.Error(err, "http - v1 - list posts - parsing limit to int")
-		errorResponse(c, http.StatusInternalServerError, "list posts limit parse error")
+		p.l.Error(err, "http - v1 - list posts - parse filter")
 
 		return
 	}
 
-	req.Page = int64(pageToInt)
-	req.Limit = int64(LimitToInt)
-
 	posts, err := p.t.ListPosts(c.Request.Context(), &req)
 	if err != nil {
 		p.l.Error(err, "http - v1 - list posts")
-		errorResponse(c, http.StatusInternalServerError, " list post service problems")
+		serviceErrorResponse(c, err, "list post service problems")
 
 		return
 	}
@@ -329,10 +661,26 @@ func (p *postRoutes) ListPosts(c *gin.Context) {
 // @Accept json
 // @Param page path string true "page"
 // @Param limit path string true "limit"
-// @Param orderBy query string false "orderBy" Enums(content, title, category, created_at, updated_at) "Order by"
+// @Param sort query string false "comma separated fields, - for descending: likes, views, created_at, updated_at, title" example(-likes,created_at)
+// @Param category query []string false "only these categories" collectionFormat(csv)
+// @Param ids query []string false "only these post ids" collectionFormat(csv)
+// @Param title_prefix query string false "title starts with (case-insensitive)"
+// @Param created_from query string false "created at or after (RFC 3339)"
+// @Param created_to query string false "created before (RFC 3339)"
+// @Param updated_from query string false "updated at or after (RFC 3339)"
+// @Param updated_to query string false "updated before (RFC 3339)"
+// @Param min_likes query int false "at least this many likes"
+// @Param min_views query int false "at least this many views"
+// @Param tags query []string false "tagged with any of these tags" collectionFormat(csv)
+// @Param tags_all query []string false "tagged with all of these tags" collectionFormat(csv)
+// @Param include_deleted query bool false "include soft-deleted posts (admin only)"
+// @Param status query []string false "statuses other than published are for moderators, admins and authors listing their own posts" collectionFormat(csv) default(published)
+// @Param with_total query bool false "count all matching posts" default(true)
 // @Param user_id path string true "user_id"
 // @Success 201 {object} entity.Posts
 // @Failure 400 {object} response
+// @Failure 403 {object} response
+// @Failure 429 {object} response
 // @Failure 500 {object} response
 func (p *postRoutes) ListPostsByUserId(c *gin.Context) {
 	var jspbMarshal protojson.MarshalOptions
@@ -341,44 +689,304 @@ func (p *postRoutes) ListPostsByUserId(c *gin.Context) {
 	var (
 		req entity.GetListFilter
 	)
-	orderBy := c.Query("orderBy")
-	if orderBy != "" {
-		req.OrderBy = orderBy
-	} else {
-		req.OrderBy = "created_at"
-	}
 
 	userId := c.Param("user_id")
 	req.UserId = userId
 
-	page := c.Param("page")
-	pageToInt, err := strconv.Atoi(page)
+	err := p.parseListFilter(c, &req)
 	if err != nil {
-		p.l.Error(err, "http - v1 - list posts - parsing page to int")
-		errorResponse(c, http.StatusInternalServerError, "list posts page parse error")
+		p.l.Error(err, "http - v1 - list posts - parse filter")
 
 		return
 	}
 
-	limit := c.Param("limit")
-	LimitToInt, err := strconv.Atoi(limit)
+	posts, err := p.t.ListPosts(c.Request.Context(), &req)
 	if err != nil {
-		p.l.Error(err, "http - v1 - list posts - parsing limit to int")
-		errorResponse(c, http.StatusInternalServerError, "list posts limit parse error")
+		p.l.Error(err, "http - v1 - list posts by user id")
+		serviceErrorResponse(c, err, "list post by user id service problems")
 
 		return
 	}
 
-	req.Page = int64(pageToInt)
-	req.Limit = int64(LimitToInt)
+	c.JSON(http.StatusOK, posts)
+}
 
-	posts, err := p.t.ListPosts(c.Request.Context(), &req)
+// List Posts By Cursor
+// @Router /posts [get]
+// @Summary get posts feed
+// @Tags Post
+// @Description get posts newest first using cursor pagination
+// @Accept json
+// @Param limit query int false "page size" default(20)
+// @Param cursor query string false "next_cursor or prev_cursor of a previous page"
+// @Param user_id query string false "only posts of this user"
+// @Param category query []string false "only these categories" collectionFormat(csv)
+// @Param ids query []string false "only these post ids" collectionFormat(csv)
+// @Param title_prefix query string false "title starts with (case-insensitive)"
+// @Param created_from query string false "created at or after (RFC 3339)"
+// @Param created_to query string false "created before (RFC 3339)"
+// @Param updated_from query string false "updated at or after (RFC 3339)"
+// @Param updated_to query string false "updated before (RFC 3339)"
+// @Param min_likes query int false "at least this many likes"
+// @Param min_views query int false "at least this many views"
+// @Param tags query []string false "tagged with any of these tags" collectionFormat(csv)
+// @Param tags_all query []string false "tagged with all of these tags" collectionFormat(csv)
+// @Param include_deleted query bool false "include soft-deleted posts (admin only)"
+// @Param status query []string false "statuses other than published are for moderators, admins and authors listing their own posts" collectionFormat(csv) default(published)
+// @Success 201 {object} entity.Posts
+// @Failure 400 {object} response
+// @Failure 403 {object} response
+// @Failure 422 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) ListPostsByCursor(c *gin.Context) {
+	req := entity.GetListFilter{
+		Limit:  _defaultLimit,
+		Cursor: c.Query("cursor"),
+		UserId: c.Query("user_id"),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		limitToInt, err := strconv.Atoi(limit)
+		if err != nil || limitToInt < 1 || limitToInt > _maxLimit {
+			p.l.Error(err, "http - v1 - list posts by cursor - parsing limit to int")
+			errorResponse(c, http.StatusBadRequest, "list posts limit parse error")
+
+			return
+		}
+
+		req.Limit = int64(limitToInt)
+	}
+
+	err := parseFilters(c, &req)
 	if err != nil {
-		p.l.Error(err, "http - v1 - list posts by user id")
-		errorResponse(c, http.StatusInternalServerError, " list post by user id service problems")
+		p.l.Error(err, "http - v1 - list posts by cursor - parse filters")
+		errorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	req.IncludeDeleted, err = p.includeDeleted(c)
+	if err != nil {
+		p.l.Error(err, "http - v1 - list posts by cursor - include_deleted")
+
+		return
+	}
+
+	req.Statuses, err = p.statuses(c)
+	if err != nil {
+		p.l.Error(err, "http - v1 - list posts by cursor - status")
+
+		return
+	}
+
+	posts, err := p.t.ListPostsByCursor(c.Request.Context(), &req)
+	if err != nil {
+		p.l.Error(err, "http - v1 - list posts by cursor")
+		serviceErrorResponse(c, err, "list post service problems")
 
 		return
 	}
 
 	c.JSON(http.StatusOK, posts)
 }
+
+// Search Posts
+// @Router /posts/search [get]
+// @Summary search posts
+// @Tags Post
+// @Description full-text search over post titles and content, most relevant first
+// @Accept json
+// @Param q query string true "search query (websearch syntax: words, \"phrases\", or, -exclude)"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(20)
+// @Param sort query string false "comma separated fields, - for descending; defaults to relevance" example(-likes,created_at)
+// @Param category query []string false "only these categories" collectionFormat(csv)
+// @Param ids query []string false "only these post ids" collectionFormat(csv)
+// @Param title_prefix query string false "title starts with (case-insensitive)"
+// @Param created_from query string false "created at or after (RFC 3339)"
+// @Param created_to query string false "created before (RFC 3339)"
+// @Param updated_from query string false "updated at or after (RFC 3339)"
+// @Param updated_to query string false "updated before (RFC 3339)"
+// @Param min_likes query int false "at least this many likes"
+// @Param min_views query int false "at least this many views"
+// @Param tags query []string false "tagged with any of these tags" collectionFormat(csv)
+// @Param tags_all query []string false "tagged with all of these tags" collectionFormat(csv)
+// @Param include_deleted query bool false "include soft-deleted posts (admin only)"
+// @Param status query []string false "statuses other than published are for moderators, admins and authors listing their own posts" collectionFormat(csv) default(published)
+// @Param with_total query bool false "count all matching posts" default(true)
+// @Success 201 {object} entity.SearchResults
+// @Failure 400 {object} response
+// @Failure 403 {object} response
+// @Failure 422 {object} entity.ValidationError
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) SearchPosts(c *gin.Context) {
+	req := entity.SearchFilter{
+		Query: c.Query("q"),
+	}
+
+	err := p.parseListFilter(c, &req.GetListFilter)
+	if err != nil {
+		p.l.Error(err, "http - v1 - search posts - parse filter")
+
+		return
+	}
+
+	results, err := p.t.SearchPosts(c.Request.Context(), &req)
+	if err != nil {
+		p.l.Error(err, "http - v1 - search posts")
+		serviceErrorResponse(c, err, "search posts service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// List Tags
+// @Router /tags [get]
+// @Summary list tags
+// @Tags Post
+// @Description tags of live posts with the number of posts using them, most used first
+// @Accept json
+// @Param prefix query string false "tag starts with"
+// @Param limit query int false "limit" default(20)
+// @Success 201 {object} entity.Tags
+// @Failure 400 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) ListTags(c *gin.Context) {
+	rawLimit := c.DefaultQuery("limit", strconv.Itoa(_defaultLimit))
+
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 || limit > _maxLimit {
+		p.l.Error(fmt.Errorf("parsing limit %q: %w", rawLimit, err), "http - v1 - list tags")
+		errorResponse(c, http.StatusBadRequest, "list tags limit parse error")
+
+		return
+	}
+
+	tags, err := p.t.ListTags(c.Request.Context(), &entity.TagFilter{
+		Prefix: c.Query("prefix"),
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		p.l.Error(err, "http - v1 - list tags")
+		serviceErrorResponse(c, err, "list tags service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// parseListFilter fills the page, limit and query-string options shared by
+// the offset-paginated list endpoints. Page and limit come from the path when
+// the route has them and from the query string otherwise. On error the
+// response has already been written.
+func (p *postRoutes) parseListFilter(c *gin.Context, req *entity.GetListFilter) error {
+	rawPage, rawLimit := c.Param("page"), c.Param("limit")
+	if rawPage == "" {
+		rawPage = c.DefaultQuery("page", "1")
+	}
+	if rawLimit == "" {
+		rawLimit = c.DefaultQuery("limit", strconv.Itoa(_defaultLimit))
+	}
+
+	page, err := strconv.Atoi(rawPage)
+	if err != nil || page < 1 {
+		errorResponse(c, http.StatusBadRequest, "list posts page parse error")
+
+		return fmt.Errorf("parsing page %q: %w", rawPage, err)
+	}
+
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 || limit > _maxLimit {
+		errorResponse(c, http.StatusBadRequest, "list posts limit parse error")
+
+		return fmt.Errorf("parsing limit %q: %w", rawLimit, err)
+	}
+
+	req.Page = int64(page)
+	req.Limit = int64(limit)
+
+	// orderBy is the pre-sort spelling of the parameter.
+	spec := c.Query("sort")
+	if spec == "" {
+		spec = c.Query("orderBy")
+	}
+	req.Sort, err = parseSort(spec)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+
+		return fmt.Errorf("parsing sort: %w", err)
+	}
+
+	req.WithTotal = true
+	if raw := c.Query("with_total"); raw != "" {
+		req.WithTotal, err = strconv.ParseBool(raw)
+		if err != nil {
+			errorResponse(c, http.StatusBadRequest, "invalid with_total")
+
+			return fmt.Errorf("parsing with_total: %w", err)
+		}
+	}
+
+	err = parseFilters(c, req)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, err.Error())
+
+		return fmt.Errorf("parsing filters: %w", err)
+	}
+
+	req.IncludeDeleted, err = p.includeDeleted(c)
+	if err != nil {
+		return fmt.Errorf("include_deleted: %w", err)
+	}
+
+	req.Statuses, err = p.statuses(c)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+
+	return nil
+}
+
+// includeDeleted parses the include_deleted flag. The use case decides who
+// may set it. On error the response has already been written.
+func (p *postRoutes) includeDeleted(c *gin.Context) (bool, error) {
+	raw := c.Query("include_deleted")
+	if raw == "" {
+		return false, nil
+	}
+
+	include, err := strconv.ParseBool(raw)
+	if err != nil {
+		errorResponse(c, http.StatusBadRequest, "invalid include_deleted")
+
+		return false, err
+	}
+
+	return include, nil
+}
+
+// statuses parses the status filter, which defaults to published posts. The
+// use case decides who may list posts in other statuses. On error the
+// response has already been written.
+func (p *postRoutes) statuses(c *gin.Context) ([]string, error) {
+	statuses := queryList(c, "status")
+	if len(statuses) == 0 {
+		return []string{entity.StatusPublished}, nil
+	}
+
+	for _, status := range statuses {
+		if !entity.IsStatus(status) {
+			errorResponse(c, http.StatusBadRequest, fmt.Sprintf("unknown status %q", status))
+
+			return nil, fmt.Errorf("unknown status %q", status)
+		}
+	}
+
+	return statuses, nil
+}
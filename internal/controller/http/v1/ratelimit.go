@@ -0,0 +1,148 @@
+package v1
+
+import (
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Route groups a rate limit policy can be configured for. Groups without a
+// policy aren't limited.
+const (
+	limitDefault  = "default"  // every /v1 request
+	limitPosts    = "posts"    // /v1/post routes, comments aside
+	limitComments = "comments" // requests under /v1/post/:id/comments
+	limitReact    = "react"    // likes, dislikes and their removal
+
+	// limitAPIKeyFailures is the failed API key attempts of a client IP.
+	// Unlike the others it applies without a policy, at _apiKeyFailures.
+	limitAPIKeyFailures = "apikey_failures"
+)
+
+// _apiKeyFailures is the rate of limitAPIKeyFailures when no policy is
+// configured for it.
+var _apiKeyFailures = ratelimit.PerMinute(10)
+
+// _rateLimitRemaining keeps, in the gin context, the fewest requests left in
+// any bucket a request took a token from, whose headers the response carries.
+const _rateLimitRemaining = "ratelimit.remaining"
+
+// rateLimiter holds clients to the policies of the route groups.
+type rateLimiter struct {
+	store    ratelimit.Store
+	policies map[string]ratelimit.Rate
+	l        logger.Interface
+}
+
+// limit takes a token from the client's bucket of group for every request,
+// rejecting the request with 429 once it is empty.
+func (r *rateLimiter) limit(group string) gin.HandlerFunc {
+	rate, ok := r.policies[group]
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if !r.allow(c, group+":"+client(c), rate) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow takes a token from the bucket of key and reports whether the request
+// may go on, having responded 429 if not. The request goes on when the store
+// fails: better to serve a request too many than none.
+func (r *rateLimiter) allow(c *gin.Context, key string, rate ratelimit.Rate) bool {
+	res, err := r.store.Take(c.Request.Context(), key, rate)
+	if err != nil {
+		r.l.Error(err, "http - v1 - rateLimiter - r.store.Take")
+
+		return true
+	}
+
+	if remaining, ok := c.Get(_rateLimitRemaining); res.Allowed && ok && remaining.(int) <= res.Remaining {
+		return true
+	}
+	c.Set(_rateLimitRemaining, res.Remaining)
+
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", seconds(res.Reset))
+	c.Header("RateLimit-Policy", rate.Policy())
+
+	if !res.Allowed {
+		c.Header("Retry-After", seconds(res.RetryAfter))
+		errorResponse(c, http.StatusTooManyRequests, "rate limit exceeded")
+
+		return false
+	}
+
+	return true
+}
+
+// attempt reports whether the client IP may try an API key, having responded
+// 429 if too many of its attempts failed lately. Like allow, it lets the
+// request go on when the store fails.
+func (r *rateLimiter) attempt(c *gin.Context) bool {
+	res, err := r.store.Peek(c.Request.Context(), r.failuresKey(c), r.failuresRate())
+	if err != nil {
+		r.l.Error(err, "http - v1 - rateLimiter - r.store.Peek")
+
+		return true
+	}
+
+	if !res.Allowed {
+		c.Header("Retry-After", seconds(res.RetryAfter))
+		errorResponse(c, http.StatusTooManyRequests, "too many failed api key attempts")
+
+		return false
+	}
+
+	return true
+}
+
+// fail counts a failed API key attempt against the client IP.
+func (r *rateLimiter) fail(c *gin.Context) {
+	if _, err := r.store.Take(c.Request.Context(), r.failuresKey(c), r.failuresRate()); err != nil {
+		r.l.Error(err, "http - v1 - rateLimiter - r.store.Take")
+	}
+}
+
+func (r *rateLimiter) failuresKey(c *gin.Context) string {
+	return limitAPIKeyFailures + ":ip:" + c.ClientIP()
+}
+
+func (r *rateLimiter) failuresRate() ratelimit.Rate {
+	if rate, ok := r.policies[limitAPIKeyFailures]; ok {
+		return rate
+	}
+
+	return _apiKeyFailures
+}
+
+// client identifies who a request counts against: the API key or the user it
+// authenticated as, else its IP, which only trusted proxies may forward.
+func client(c *gin.Context) string {
+	if p, ok := entity.PrincipalFromContext(c.Request.Context()); ok {
+		if p.APIKeyId != "" {
+			return "apikey:" + p.APIKeyId
+		}
+
+		return "user:" + p.UserId
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+// seconds renders d in whole seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,200 @@
+package v1
+
+import (
+	"errors"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// rateLimitEngine authenticates and limits requests like the /v1 group does,
+// GET / taking a token from the default and posts groups.
+func rateLimitEngine(t *testing.T, store ratelimit.Store, trusted ...string) *gin.Engine {
+	t.Helper()
+
+	rl := &rateLimiter{
+		store: store,
+		policies: map[string]ratelimit.Rate{
+			limitDefault: ratelimit.PerMinute(10),
+			limitPosts:   {Limit: 5, Period: 30 * time.Second},
+		},
+		l: nopLogger{},
+	}
+
+	e := gin.New()
+	require.NoError(t, e.SetTrustedProxies(trusted))
+
+	h := e.Group("/",
+		authenticate(verifier(t), nopLogger{}),
+		authenticateAPIKey(fakeAPIKeys{"reader": _readerKey}, rl, nopLogger{}),
+		rl.limit(limitDefault),
+	)
+	h.GET("/", rl.limit(limitPosts), func(c *gin.Context) { c.Status(http.StatusOK) })
+	h.GET("/unlimited", rl.limit(limitComments), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return e
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		results map[string]ratelimit.Result
+		want    map[string]string
+	}{
+		{
+			name: "fresh buckets",
+			want: map[string]string{
+				"RateLimit-Limit":     "5",
+				"RateLimit-Remaining": "4",
+				"RateLimit-Reset":     "30",
+				"RateLimit-Policy":    "5;w=30",
+			},
+		},
+		{
+			name: "later bucket has fewer left",
+			results: map[string]ratelimit.Result{
+				limitDefault: {Allowed: true, Limit: 10, Remaining: 6, Reset: 20 * time.Second},
+				limitPosts:   {Allowed: true, Limit: 5, Remaining: 2, Reset: 1500 * time.Millisecond},
+			},
+			want: map[string]string{
+				"RateLimit-Limit":     "5",
+				"RateLimit-Remaining": "2",
+				"RateLimit-Reset":     "2",
+				"RateLimit-Policy":    "5;w=30",
+			},
+		},
+		{
+			name: "earlier bucket has fewer left",
+			results: map[string]ratelimit.Result{
+				limitDefault: {Allowed: true, Limit: 10, Remaining: 1, Reset: 54 * time.Second},
+				limitPosts:   {Allowed: true, Limit: 5, Remaining: 3, Reset: 12 * time.Second},
+			},
+			want: map[string]string{
+				"RateLimit-Limit":     "10",
+				"RateLimit-Remaining": "1",
+				"RateLimit-Reset":     "54",
+				"RateLimit-Policy":    "10;w=60",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serve(rateLimitEngine(t, &fakeStore{results: tc.results}), http.MethodGet, "/", nil)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			for header, want := range tc.want {
+				require.Equal(t, want, w.Header().Get(header), header)
+			}
+			require.Empty(t, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		group string
+	}{
+		{"default", limitDefault},
+		{"posts", limitPosts},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &fakeStore{results: map[string]ratelimit.Result{
+				tc.group: {Limit: 3, RetryAfter: 2500 * time.Millisecond, Reset: 9 * time.Second},
+			}}
+
+			w := serve(rateLimitEngine(t, store), http.MethodGet, "/", nil)
+			require.Equal(t, http.StatusTooManyRequests, w.Code)
+			require.JSONEq(t, `{"error": "rate limit exceeded"}`, w.Body.String())
+			require.Equal(t, "3", w.Header().Get("Retry-After"))
+			require.Equal(t, "3", w.Header().Get("RateLimit-Limit"))
+			require.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+			require.Equal(t, "9", w.Header().Get("RateLimit-Reset"))
+
+			if tc.group == limitDefault {
+				require.Len(t, store.taken(), 1, "a rejected request stops there")
+			}
+		})
+	}
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	t.Parallel()
+
+	w := serve(rateLimitEngine(t, &fakeStore{err: errors.New("down")}), http.MethodGet, "/", nil)
+	require.Equal(t, http.StatusOK, w.Code, "requests go on when the store fails")
+	require.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitWithoutPolicy(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeStore{}
+
+	w := serve(rateLimitEngine(t, store), http.MethodGet, "/unlimited", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, []string{"default:ip:192.0.2.1"}, store.taken(), "groups without a policy aren't limited")
+}
+
+func TestRateLimitClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		trusted []string
+		header  map[string]string
+		want    string
+	}{
+		{"ip", nil, nil, "ip:192.0.2.1"},
+		{
+			"forwarded by a trusted proxy", []string{"192.0.2.1"},
+			map[string]string{"X-Forwarded-For": "198.51.100.7"}, "ip:198.51.100.7",
+		},
+		{
+			"forwarded by an untrusted proxy", []string{"192.0.2.9"},
+			map[string]string{"X-Forwarded-For": "198.51.100.7"}, "ip:192.0.2.1",
+		},
+		{"user", nil, map[string]string{"Authorization": bearer(t, _userId)}, "user:" + _userId},
+		{"api key", nil, map[string]string{apiKeyHeader: "reader"}, "apikey:reader"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &fakeStore{}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			rateLimitEngine(t, store, tc.trusted...).ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, []string{"default:" + tc.want, "posts:" + tc.want}, store.taken())
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type revertRequest struct {
+	Version int64 `json:"version" example:"3"`
+}
+
+// List Revisions
+// @Router /post/{id}/revisions [get]
+// @Summary list revisions
+// @Tags Revision
+// @Description Earlier versions of a post's content, newest first. Posts that aren't published are only found by their authors, moderators and admins
+// @Produce json
+// @Param id path string true "id"
+// @Param page query int false "page" default(1)
+// @Param limit query int false "limit" default(20)
+// @Success 201 {object} entity.Revisions
+// @Failure 400 {object} response
+// @Failure 404 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) ListRevisions(c *gin.Context) {
+	req := entity.RevisionFilter{PostId: c.Param("id")}
+
+	rawPage := c.DefaultQuery("page", "1")
+	page, err := strconv.Atoi(rawPage)
+	if err != nil || page < 1 {
+		p.l.Error(fmt.Errorf("parsing page %q: %w", rawPage, err), "http - v1 - list revisions")
+		errorResponse(c, http.StatusBadRequest, "list revisions page parse error")
+
+		return
+	}
+
+	rawLimit := c.DefaultQuery("limit", strconv.Itoa(_defaultLimit))
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 || limit > _maxLimit {
+		p.l.Error(fmt.Errorf("parsing limit %q: %w", rawLimit, err), "http - v1 - list revisions")
+		errorResponse(c, http.StatusBadRequest, "list revisions limit parse error")
+
+		return
+	}
+
+	req.Page = int64(page)
+	req.Limit = int64(limit)
+
+	revisions, err := p.t.ListRevisions(c.Request.Context(), &req)
+	if err != nil {
+		p.l.Error(err, "http - v1 - list revisions")
+		serviceErrorResponse(c, err, "list revisions service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// Get Revision
+// @Router /post/{id}/revisions/{version} [get]
+// @Summary get revision
+// @Tags Revision
+// @Description The content of a post at the given version. Posts that aren't published are only found by their authors, moderators and admins
+// @Produce json
+// @Param id path string true "id"
+// @Param version path int true "version"
+// @Success 201 {object} entity.Revision
+// @Failure 400 {object} response
+// @Failure 404 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) GetRevision(c *gin.Context) {
+	version, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil {
+		p.l.Error(err, "http - v1 - get revision")
+		errorResponse(c, http.StatusBadRequest, "invalid version")
+
+		return
+	}
+
+	revision, err := p.t.GetRevision(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		p.l.Error(err, "http - v1 - get revision")
+		serviceErrorResponse(c, err, "get revision service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// Diff Revisions
+// @Router /post/{id}/revisions/diff [get]
+// @Summary diff revisions
+// @Tags Revision
+// @Description What changed in a post between two versions; content is diffed line by line. Posts that aren't published are only found by their authors, moderators and admins
+// @Produce json
+// @Param id path string true "id"
+// @Param from query int true "older version"
+// @Param to query int true "newer version"
+// @Success 201 {object} entity.RevisionDiff
+// @Failure 400 {object} response
+// @Failure 404 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) DiffRevisions(c *gin.Context) {
+	from, err := queryInt(c, "from")
+	if err == nil && from == nil {
+		err = fmt.Errorf("from is required")
+	}
+	if err != nil {
+		p.l.Error(err, "http - v1 - diff revisions")
+		errorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	to, err := queryInt(c, "to")
+	if err == nil && to == nil {
+		err = fmt.Errorf("to is required")
+	}
+	if err != nil {
+		p.l.Error(err, "http - v1 - diff revisions")
+		errorResponse(c, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	diff, err := p.t.DiffRevisions(c.Request.Context(), c.Param("id"), *from, *to)
+	if err != nil {
+		p.l.Error(err, "http - v1 - diff revisions")
+		serviceErrorResponse(c, err, "diff revisions service problems")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// Revert Post
+// @Router /post/{id}/revisions/{version}/revert [post]
+// @Summary revert post
+// @Tags Revision
+// @Description Restore the content of an earlier revision; the replaced content becomes a revision too. Reverting to the current version is a 409
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "id"
+// @Param version path int true "revision to revert to"
+// @Param If-Match header string false "ETag of the post being edited; alternative to version in the body"
+// @Param RevertRequest body revertRequest false "current post version"
+// @Param Idempotency-Key header string false "makes retries replay the response to the first request"
+// @Success 201 {object} entity.Post
+// @Header 201 {string} ETag "post version"
+// @Failure 400 {object} response
+// @Failure 401 {object} response
+// @Failure 403 {object} response
+// @Failure 404 {object} response
+// @Failure 409 {object} response
+// @Failure 412 {object} response
+// @Failure 428 {object} response
+// @Failure 429 {object} response
+// @Failure 500 {object} response
+func (p *postRoutes) RevertPost(c *gin.Context) {
+	revision, err := strconv.ParseInt(c.Param("version"), 10, 64)
+	if err != nil {
+		p.l.Error(err, "http - v1 - revert post")
+		errorResponse(c, http.StatusBadRequest, "invalid version")
+
+		return
+	}
+
+	var body revertRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			p.l.Error(err, "http - v1 - revert post")
+			errorResponse(c, http.StatusBadRequest, "invalid request body")
+
+			return
+		}
+	}
+
+	version, err := requireVersion(c, body.Version)
+	if err != nil {
+		p.l.Error(err, "http - v1 - revert post")
+
+		return
+	}
+
+	post, err := p.t.RevertPost(c.Request.Context(), c.Param("id"), revision, version)
+	if err != nil {
+		p.l.Error(err, "http - v1 - revert post")
+		serviceErrorResponse(c, err, "revert post service problems")
+
+		return
+	}
+
+	c.Header("ETag", etag(post.Version))
+	c.JSON(http.StatusOK, post)
+}
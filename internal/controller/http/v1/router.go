@@ -2,7 +2,10 @@ package v1
 
 import (
 	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/idempotency"
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
 	"fourth-exam/post-service-clean-arch/pkg/logger"
+	"fourth-exam/post-service-clean-arch/pkg/ratelimit"
 	"net/http"
 
 	_ "fourth-exam/post-service-clean-arch/docs"
@@ -20,25 +23,44 @@ import (
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /v1
+// @securityDefinitions.apikey BearerAuth
+// @in   header
+// @name Authorization
+// @description "Bearer " followed by a JWT whose subject is the user id
+// @securityDefinitions.apikey ApiKeyAuth
+// @in   header
+// @name X-API-Key
+// @description API key of a service caller, minted with cmd/apikey
 
-func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.Post) {
-	// Options 
+func NewRouter(handler *gin.Engine, l logger.Interface, t usecase.Post, cm usecase.Comment, k usecase.APIKey,
+	v *jwtauth.Verifier, limiter ratelimit.Store, policies map[string]ratelimit.Rate,
+	idempotencyStore idempotency.Store,
+) {
+	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
 
-	// Swagger 
+	// Swagger
 	swaggerHandler := ginSwagger.DisablingWrapHandler(swaggerFiles.Handler, "DISABLE_SWAGGER_HTTP_HANDLER")
 	handler.GET("/swagger/*any", swaggerHandler)
 
-	// K8s probe 
-	handler.GET("/healthz", func(c *gin.Context) {c.Status(http.StatusOK)})
+	// K8s probe
+	handler.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
 
-	// Prometheus metrics 
+	// Prometheus metrics
 	handler.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	// Routers 
-	h := handler.Group("/v1") 
+	// Routers
+	rl := &rateLimiter{store: limiter, policies: policies, l: l}
+
+	h := handler.Group("/v1",
+		authenticate(v, l),
+		authenticateAPIKey(k, rl, l),
+		rl.limit(limitDefault),
+		idempotent(idempotencyStore, l),
+	)
 	{
-		newPostRoutes(h, t, l)
+		newPostRoutes(h, t, l, rl)
+		newCommentRoutes(h, cm, l, rl)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,90 @@
+package entity
+
+// Scopes an API key can be granted.
+const (
+	ScopePostsRead  = "posts:read"
+	ScopePostsWrite = "posts:write"
+	// ScopePostsImpersonate lets a key write posts, comments and reactions
+	// as the user it names, which a key has no user of its own to write as.
+	ScopePostsImpersonate = "posts:impersonate"
+	ScopeAdmin            = "admin"
+)
+
+// Scopes lists every scope an API key can be granted.
+var Scopes = []string{ScopePostsRead, ScopePostsWrite, ScopePostsImpersonate, ScopeAdmin}
+
+// MaxAPIKeyNameLength is the longest API key name accepted.
+const MaxAPIKeyNameLength = 100
+
+// APIKey lets a service without a user call the API. Only the hash of its
+// secret is kept; the key itself is shown once, when it is minted.
+type APIKey struct {
+	Id     string   `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Hash   []byte   `json:"-"`
+	Scopes []string `json:"scopes"`
+	// RateLimit is how many requests a minute the key may make, 0 for no limit.
+	RateLimit  int    `json:"rate_limit"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+}
+
+type APIKeys struct {
+	Count int64     `json:"count"`
+	Items []*APIKey `json:"api_keys"`
+}
+
+// HasScope reports whether the key was granted scope. The admin scope
+// includes every other.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Principal returns the caller a request made with the key acts as. It has
+// the key's scopes but no user or roles.
+func (k *APIKey) Principal() *Principal {
+	return &Principal{APIKeyId: k.Id, Scopes: k.Scopes}
+}
+
+// Validate checks the fields of a key to mint.
+func (k *APIKey) Validate() error {
+	v := &ValidationError{}
+
+	validateText(v, "name", k.Name, MaxAPIKeyNameLength)
+
+	if len(k.Scopes) == 0 {
+		v.Add("scopes", CodeRequired)
+	}
+
+	for _, s := range k.Scopes {
+		if !isScope(s) {
+			v.Add("scopes", CodeInvalidChoice)
+
+			break
+		}
+	}
+
+	if k.RateLimit < 0 {
+		v.Add("rate_limit", CodeOutOfRange)
+	}
+
+	return v.Err()
+}
+
+func isScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package entity
+
+// MaxCommentLength is the longest comment content accepted.
+const MaxCommentLength = 2000
+
+type Comment struct {
+	Id        string `json:"id"`
+	PostId    string `json:"post_id"`
+	ParentId  string `json:"parent_id,omitempty"`
+	UserId    string `json:"user_id"`
+	APIKeyId  string `json:"api_key_id,omitempty"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// CommentFilter lists the comments of a post, oldest first. With ParentId
+// set only the direct replies to that comment are listed.
+type CommentFilter struct {
+	PostId   string `json:"post_id"`
+	ParentId string `json:"parent_id"`
+	Page     int64  `json:"page"`
+	Limit    int64  `json:"limit"`
+}
+
+type Comments struct {
+	Count int64      `json:"count"`
+	Items []*Comment `json:"comments"`
+
+	Total   int64 `json:"total"`
+	Page    int64 `json:"page"`
+	Limit   int64 `json:"limit"`
+	HasMore bool  `json:"has_more"`
+}
+
+// Validate checks the client-supplied fields of a comment.
+func (c *Comment) Validate() error {
+	v := &ValidationError{}
+
+	switch {
+	case c.UserId == "":
+		v.Add("user_id", CodeRequired)
+	case !isUUID(c.UserId):
+		v.Add("user_id", CodeInvalidUUID)
+	}
+
+	if c.ParentId != "" && !isUUID(c.ParentId) {
+		v.Add("parent_id", CodeInvalidUUID)
+	}
+
+	validateText(v, "content", c.Content, MaxCommentLength)
+
+	return v.Err()
+}
+
+// ValidateEdit checks a comment edit, which may only change the content.
+func (c *Comment) ValidateEdit() error {
+	v := &ValidationError{}
+
+	validateText(v, "content", c.Content, MaxCommentLength)
+
+	return v.Err()
+}
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Domain errors. Repositories and use cases wrap them so callers can react to
+// the kind of failure with errors.Is instead of matching driver errors.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrValidation      = errors.New("validation failed")
+	ErrForbidden       = errors.New("forbidden")
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// ErrVersionConflict means the post changed since the version the caller
+// based its write on. It is an ErrConflict.
+var ErrVersionConflict = fmt.Errorf("%w: version mismatch", ErrConflict)
@@ -0,0 +1,27 @@
+package entity
+
+// Roles a principal can be granted on top of being a user.
+const (
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Actions on posts that need authorization. Published posts are public:
+// ActionViewPost is only checked for posts in other statuses, and
+// ActionListUnpublishedPosts for listings asking for other statuses.
+// ActionListDeletedPosts is checked for listings including deleted posts.
+const (
+	ActionViewPost             = "post:view"
+	ActionEditPost             = "post:edit"
+	ActionDeletePost           = "post:delete"
+	ActionRestorePost          = "post:restore"
+	ActionPurgePosts           = "post:purge"
+	ActionListUnpublishedPosts = "post:list-unpublished"
+	ActionListDeletedPosts     = "post:list-deleted"
+)
+
+// Actions on comments that need authorization.
+const (
+	ActionEditComment   = "comment:edit"
+	ActionDeleteComment = "comment:delete"
+)
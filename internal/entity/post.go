@@ -1,32 +1,135 @@
 package entity
 
+import "time"
+
+// TimestampLayout is the format of the timestamp fields of Post.
+const TimestampLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
 type Post struct {
-	Id        string `json:"id"`
-	UserId    string `json:"user_id"`
-	Content   string `json:"content"`
-	Title     string `json:"title"`
-	Likes     int64  `json:"likes"`
-	Dislikes  int64  `json:"dislikes"`
-	Views     int64  `json:"views"`
-	Category  string `json:"category"`
+	Id       string `json:"id"`
+	UserId   string `json:"user_id"`
+	APIKeyId string `json:"api_key_id,omitempty"`
+	Content  string `json:"content"`
+	Title    string `json:"title"`
+	Likes    int64  `json:"likes"`
+	Dislikes int64  `json:"dislikes"`
+	Views    int64  `json:"views"`
+	Category string `json:"category"`
+
+	Tags          []string `json:"tags"`
+	CommentsCount int64    `json:"comments_count"`
+
+	// Status defaults to draft on create. PublishedAt is when the post went,
+	// or for scheduled posts will go, live; clients only set it (RFC 3339)
+	// when creating a scheduled post.
+	Status      string `json:"status"`
+	PublishedAt string `json:"published_at,omitempty"`
+
+	Version   int64  `json:"version"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+	DeletedAt string `json:"deleted_at,omitempty"`
+}
+
+// PostPatch is a partial update of a post: nil fields are left unchanged.
+// Version is the version the patch is based on.
+type PostPatch struct {
+	Title    *string   `json:"title,omitempty"`
+	Content  *string   `json:"content,omitempty"`
+	Category *string   `json:"category,omitempty"`
+	Tags     *[]string `json:"tags,omitempty"`
+	Version  int64     `json:"version"`
 }
 
 type GetListFilter struct {
-	Page    int64  `json:"page"`
-	Limit   int64  `json:"limit"`
-	OrderBy string `json:"order_by"`
-	UserId  string `json:"user_id"`
+	Page   int64       `json:"page"`
+	Limit  int64       `json:"limit"`
+	Sort   []SortField `json:"sort"`
+	UserId string      `json:"user_id"`
+
+	// Optional filters; time ranges include From and exclude To.
+	Categories  []string   `json:"categories"`
+	Ids         []string   `json:"ids"`
+	TitlePrefix string     `json:"title_prefix"`
+	CreatedFrom *time.Time `json:"created_from"`
+	CreatedTo   *time.Time `json:"created_to"`
+	UpdatedFrom *time.Time `json:"updated_from"`
+	UpdatedTo   *time.Time `json:"updated_to"`
+	MinLikes    *int64     `json:"min_likes"`
+	MinViews    *int64     `json:"min_views"`
+	Statuses    []string   `json:"statuses"`
+
+	// TagsAny matches posts with at least one of the tags, TagsAll posts
+	// with every one of them.
+	TagsAny []string `json:"tags_any"`
+	TagsAll []string `json:"tags_all"`
+
+	IncludeDeleted bool `json:"include_deleted"`
+	WithTotal      bool `json:"with_total"`
+
+	// Cursor is the opaque token of a previous page; Keyset and Backward
+	// are the position it decodes to.
+	Cursor   string  `json:"cursor"`
+	Keyset   *Keyset `json:"-"`
+	Backward bool    `json:"-"`
 }
 
+// SortableFields are the post fields a listing can be ordered by.
+var SortableFields = []string{"likes", "views", "created_at", "updated_at", "title"}
+
+// SortField is one key of a listing order.
+type SortField struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// IsSortable reports whether field is one of SortableFields.
+func IsSortable(field string) bool {
+	for _, f := range SortableFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Keyset is the position of a post in the (created_at, id) feed ordering.
+type Keyset struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        string    `json:"id"`
+}
+
+// Posts is a page of posts. Count is the number of items on the page; Total
+// is the number of posts matching the filter, when it was requested.
 type Posts struct {
 	Count int64   `json:"count"`
 	Items []*Post `json:"posts"`
+
+	Total      *int64 `json:"total,omitempty"`
+	Page       int64  `json:"page,omitempty"`
+	Limit      int64  `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Keyset returns the post's position in the feed ordering.
+func (p *Post) Keyset() (Keyset, error) {
+	createdAt, err := time.Parse(TimestampLayout, p.CreatedAt)
+	if err != nil {
+		return Keyset{}, err
+	}
+
+	return Keyset{CreatedAt: createdAt, Id: p.Id}, nil
 }
 
+// PostRequest names a post to react to. The HTTP API reacts as the
+// authenticated user; UserId is only honoured for API keys with the
+// posts:impersonate scope, which react on behalf of the user it names.
 type PostRequest struct {
 	PostId string `json:"post_id"`
+	UserId string `json:"user_id"`
 }
 
 type MessageResponse struct {
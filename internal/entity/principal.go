@@ -0,0 +1,49 @@
+package entity
+
+import "context"
+
+// Principal is the authenticated caller of a request: a user, or a service
+// calling with an API key, which has scopes but no user.
+type Principal struct {
+	UserId   string   `json:"user_id"`
+	Roles    []string `json:"roles"`
+	APIKeyId string   `json:"api_key_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// HasRole reports whether the principal was granted role.
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasScope reports whether the API key of the principal was granted scope.
+// The admin scope includes every other.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+type principalKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying p.
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal ctx carries, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+
+	return p, ok && p != nil
+}
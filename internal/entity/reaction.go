@@ -0,0 +1,13 @@
+package entity
+
+// Reaction kinds a user can leave on a post.
+const (
+	ReactionLike    = "like"
+	ReactionDislike = "dislike"
+)
+
+type Reaction struct {
+	PostId   string `json:"post_id"`
+	UserId   string `json:"user_id"`
+	Reaction string `json:"reaction"`
+}
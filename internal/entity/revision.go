@@ -0,0 +1,74 @@
+package entity
+
+// Revision is the editable content of a post as it was at Version.
+// Revisions are numbered by the post version they capture; versions that
+// only changed the status have no revision of their own.
+type Revision struct {
+	PostId   string   `json:"post_id"`
+	Version  int64    `json:"version"`
+	UserId   string   `json:"user_id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+	EditedAt string   `json:"edited_at"`
+}
+
+// RevisionFilter lists the revisions of a post, newest first.
+type RevisionFilter struct {
+	PostId string `json:"post_id"`
+	Page   int64  `json:"page"`
+	Limit  int64  `json:"limit"`
+}
+
+type Revisions struct {
+	Count int64       `json:"count"`
+	Items []*Revision `json:"revisions"`
+
+	Page    int64 `json:"page"`
+	Limit   int64 `json:"limit"`
+	HasMore bool  `json:"has_more"`
+}
+
+// RevisionDiff is what changed between two revisions of a post. Title and
+// Category are only set when they changed; Content lists every line.
+type RevisionDiff struct {
+	PostId      string       `json:"post_id"`
+	From        int64        `json:"from"`
+	To          int64        `json:"to"`
+	Title       *FieldChange `json:"title,omitempty"`
+	Category    *FieldChange `json:"category,omitempty"`
+	Content     []LineDiff   `json:"content"`
+	TagsAdded   []string     `json:"tags_added"`
+	TagsRemoved []string     `json:"tags_removed"`
+}
+
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// LineDiff is one line of a content diff; Op is equal, insert or delete.
+type LineDiff struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Revision returns the current content of the post as a revision.
+func (p *Post) Revision() *Revision {
+	editedAt := p.UpdatedAt
+	if editedAt == "" {
+		editedAt = p.CreatedAt
+	}
+
+	return &Revision{
+		PostId:   p.Id,
+		Version:  p.Version,
+		UserId:   p.UserId,
+		Title:    p.Title,
+		Content:  p.Content,
+		Category: p.Category,
+		Tags:     p.Tags,
+		EditedAt: editedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package entity
+
+// SearchLanguages are the Postgres text search configurations posts may be
+// indexed in.
+var SearchLanguages = []string{
+	"simple", "english", "german", "french", "spanish", "italian",
+	"portuguese", "russian", "turkish",
+}
+
+// SearchFilter is a full-text query plus the usual list filters and pagination.
+type SearchFilter struct {
+	GetListFilter
+
+	Query string `json:"q"`
+}
+
+// SearchResult is a matching post with its relevance and a highlighted
+// fragment of its content.
+type SearchResult struct {
+	*Post
+
+	Rank     float32 `json:"rank"`
+	Headline string  `json:"headline"`
+}
+
+type SearchResults struct {
+	Count int64           `json:"count"`
+	Items []*SearchResult `json:"results"`
+
+	Total   *int64 `json:"total,omitempty"`
+	Page    int64  `json:"page"`
+	Limit   int64  `json:"limit"`
+	HasMore bool   `json:"has_more"`
+}
+
+// IsSearchLanguage reports whether lang is one of SearchLanguages.
+func IsSearchLanguage(lang string) bool {
+	for _, l := range SearchLanguages {
+		if l == lang {
+			return true
+		}
+	}
+
+	return false
+}
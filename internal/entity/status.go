@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Post statuses. Only published posts show up in public listings.
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// Statuses lists every post status.
+var Statuses = []string{StatusDraft, StatusScheduled, StatusPublished, StatusArchived}
+
+// ErrInvalidTransition is returned when a post can't move to the requested status.
+var ErrInvalidTransition = fmt.Errorf("%w: invalid status transition", ErrConflict)
+
+// statusTransitions lists the statuses each status can move to.
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusScheduled, StatusPublished, StatusArchived},
+	StatusScheduled: {StatusScheduled, StatusPublished, StatusDraft, StatusArchived},
+	StatusPublished: {StatusDraft, StatusArchived},
+	StatusArchived:  {StatusDraft},
+}
+
+// StatusChange moves a post from one status to another. PublishedAt, when
+// set, replaces published_at and ClearPublishedAt empties it; otherwise it
+// is left as is.
+type StatusChange struct {
+	From             string
+	To               string
+	PublishedAt      *time.Time
+	ClearPublishedAt bool
+}
+
+// IsStatus reports whether status is one of Statuses.
+func IsStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanTransition reports whether a post in status from may move to status to.
+func CanTransition(from, to string) bool {
+	for _, s := range statusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+type PublishRequest struct {
+	// PublishAt schedules the post; when empty or past the post goes live now.
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Post tag limits.
+const (
+	MaxTags      = 10
+	MaxTagLength = 32
+)
+
+type Tag struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// TagFilter lists the most used tags, optionally only those starting with Prefix.
+type TagFilter struct {
+	Prefix string `json:"prefix"`
+	Limit  int64  `json:"limit"`
+}
+
+type Tags struct {
+	Count int64  `json:"count"`
+	Items []*Tag `json:"tags"`
+}
+
+// NormalizeTag turns a tag into its slug: lowercase letters and digits,
+// with every other run of characters collapsed into a single dash.
+func NormalizeTag(tag string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+
+	for _, r := range strings.ToLower(tag) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return b.String()
+}
+
+// NormalizeTags normalizes every tag, dropping empty ones and duplicates.
+// The result is sorted and never nil.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+
+	return normalized
+}
@@ -0,0 +1,243 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Field error codes reported in ValidationError.
+const (
+	CodeRequired      = "required"
+	CodeTooLong       = "too_long"
+	CodeInvalidUUID   = "invalid_uuid"
+	CodeInvalidChoice = "invalid_choice"
+	CodeReadOnly      = "read_only"
+	CodeUnknownField  = "unknown_field"
+	CodeNotFound      = "not_found"
+	CodeTooMany       = "too_many"
+	CodeInvalidTime   = "invalid_time"
+	CodeInPast        = "in_past"
+	CodeOutOfRange    = "out_of_range"
+)
+
+// Post field limits.
+const (
+	MaxTitleLength   = 255
+	MaxContentLength = 10000
+)
+
+// Categories a post can be filed under.
+var Categories = []string{
+	"Art", "Business", "Education", "Food", "Health", "Music",
+	"Nature", "News", "Science", "Sport", "Technology", "Travel", "Other",
+}
+
+type FieldError struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+}
+
+// ValidationError lists every invalid field of a request.
+// It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for _, f := range e.Errors {
+		fields = append(fields, f.Field+": "+f.Code)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(fields, ", "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Add records a failed field.
+func (e *ValidationError) Add(field, code string) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Code: code})
+}
+
+// Err returns e if any field failed, nil otherwise.
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Validate checks a new post: its author, its client-editable fields, the
+// initial status and that none of the fields the service maintains itself
+// (counters and timestamps) are set.
+func (p *Post) Validate() error {
+	v := p.validate(true)
+
+	switch p.Status {
+	case StatusDraft, StatusPublished:
+		if p.PublishedAt != "" {
+			v.Add("published_at", CodeReadOnly)
+		}
+	case StatusScheduled:
+		validatePublishTime(v, p.PublishedAt)
+	default:
+		v.Add("status", CodeInvalidChoice)
+	}
+
+	return v.Err()
+}
+
+// ValidateUpdate checks a full update of a post, which can't change the
+// status: that goes through the publish, unpublish and archive operations.
+// The author isn't checked, as an update keeps the one of the stored post.
+func (p *Post) ValidateUpdate() error {
+	v := p.validate(false)
+
+	if p.Status != "" {
+		v.Add("status", CodeReadOnly)
+	}
+	if p.PublishedAt != "" {
+		v.Add("published_at", CodeReadOnly)
+	}
+
+	return v.Err()
+}
+
+// validate checks the fields shared by creates and updates, and user_id when
+// author is set.
+func (p *Post) validate(author bool) *ValidationError {
+	v := &ValidationError{}
+
+	if p.Id != "" && !isUUID(p.Id) {
+		v.Add("id", CodeInvalidUUID)
+	}
+
+	switch {
+	case !author:
+	case p.UserId == "":
+		v.Add("user_id", CodeRequired)
+	case !isUUID(p.UserId):
+		v.Add("user_id", CodeInvalidUUID)
+	}
+
+	validateText(v, "title", p.Title, MaxTitleLength)
+	validateText(v, "content", p.Content, MaxContentLength)
+
+	switch {
+	case p.Category == "":
+		v.Add("category", CodeRequired)
+	case !isCategory(p.Category):
+		v.Add("category", CodeInvalidChoice)
+	}
+
+	validateTags(v, p.Tags)
+
+	readOnly := []struct {
+		field string
+		set   bool
+	}{
+		{"likes", p.Likes != 0},
+		{"dislikes", p.Dislikes != 0},
+		{"views", p.Views != 0},
+		{"comments_count", p.CommentsCount != 0},
+		{"created_at", p.CreatedAt != ""},
+		{"updated_at", p.UpdatedAt != ""},
+		{"deleted_at", p.DeletedAt != ""},
+	}
+	for _, f := range readOnly {
+		if f.set {
+			v.Add(f.field, CodeReadOnly)
+		}
+	}
+
+	return v
+}
+
+// Validate checks the fields a patch sets. A field explicitly set to null is
+// reported as required, since every patchable field is mandatory.
+func (p *PostPatch) Validate() error {
+	v := &ValidationError{}
+
+	if p.Title != nil {
+		validateText(v, "title", *p.Title, MaxTitleLength)
+	}
+	if p.Content != nil {
+		validateText(v, "content", *p.Content, MaxContentLength)
+	}
+	if p.Category != nil && !isCategory(*p.Category) {
+		if *p.Category == "" {
+			v.Add("category", CodeRequired)
+		} else {
+			v.Add("category", CodeInvalidChoice)
+		}
+	}
+	if p.Tags != nil {
+		validateTags(v, *p.Tags)
+	}
+
+	return v.Err()
+}
+
+func validateText(v *ValidationError, field, value string, maxLength int) {
+	switch {
+	case strings.TrimSpace(value) == "":
+		v.Add(field, CodeRequired)
+	case utf8.RuneCountInString(value) > maxLength:
+		v.Add(field, CodeTooLong)
+	}
+}
+
+// validateTags checks normalized tags.
+func validateTags(v *ValidationError, tags []string) {
+	if len(tags) > MaxTags {
+		v.Add("tags", CodeTooMany)
+	}
+
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			v.Add("tags", CodeTooLong)
+
+			return
+		}
+	}
+}
+
+// validatePublishTime checks the time a scheduled post goes live.
+func validatePublishTime(v *ValidationError, publishedAt string) {
+	if publishedAt == "" {
+		v.Add("published_at", CodeRequired)
+
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, publishedAt)
+	switch {
+	case err != nil:
+		v.Add("published_at", CodeInvalidTime)
+	case !at.After(time.Now()):
+		v.Add("published_at", CodeInPast)
+	}
+}
+
+func isCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isUUID(s string) bool {
+	_, err := uuid.Parse(s)
+
+	return err == nil
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/apikey"
+	"sync"
+	"time"
+)
+
+// APIKeyUseCase -.
+type APIKeyUseCase struct {
+	repo  APIKeyRepo
+	usage *usageBuffer
+}
+
+// usageBuffer keeps the last use of each key in memory until it is flushed,
+// so authenticating doesn't turn into a write each time.
+type usageBuffer struct {
+	mu      sync.Mutex
+	pending map[string]time.Time
+}
+
+// NewAPIKey -.
+func NewAPIKey(r APIKeyRepo) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		repo:  r,
+		usage: &usageBuffer{pending: make(map[string]time.Time)},
+	}
+}
+
+// Mint API key creates a key with the name, scopes and rate limit of req and
+// returns it together with the token to call the API with, which can't be
+// recovered later.
+func (a *APIKeyUseCase) MintAPIKey(ctx context.Context, req *entity.APIKey) (*entity.APIKey, string, error) {
+	if err := req.Validate(); err != nil {
+		return nil, "", fmt.Errorf("APIKeyUseCase - Mint - req.Validate: %w", err)
+	}
+
+	token, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, "", fmt.Errorf("APIKeyUseCase - Mint - apikey.Generate: %w", err)
+	}
+
+	req.Prefix, req.Hash = prefix, hash
+
+	key, err := a.repo.Create(ctx, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("APIKeyUseCase - Mint - a.repo: %w", err)
+	}
+
+	return key, token, nil
+}
+
+// Revoke API key, which stops authenticating right away.
+func (a *APIKeyUseCase) RevokeAPIKey(ctx context.Context, id string) error {
+	if err := a.repo.Revoke(ctx, id); err != nil {
+		return fmt.Errorf("APIKeyUseCase - Revoke - a.repo: %w", err)
+	}
+
+	return nil
+}
+
+// List API keys, revoked ones included.
+func (a *APIKeyUseCase) ListAPIKeys(ctx context.Context) (*entity.APIKeys, error) {
+	keys, err := a.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyUseCase - List - a.repo: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Authenticate API key returns the key token belongs to and records its use.
+// Malformed, unknown and revoked tokens are entity.ErrUnauthenticated.
+func (a *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, token string) (*entity.APIKey, error) {
+	prefix, secret, err := apikey.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyUseCase - Authenticate - apikey.Parse: %w", entity.ErrUnauthenticated)
+	}
+
+	key, err := a.repo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, fmt.Errorf("APIKeyUseCase - Authenticate: unknown key %s: %w", prefix, entity.ErrUnauthenticated)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyUseCase - Authenticate - a.repo: %w", err)
+	}
+
+	if !apikey.Verify(secret, key.Hash) {
+		return nil, fmt.Errorf("APIKeyUseCase - Authenticate: wrong secret for key %s: %w", prefix, entity.ErrUnauthenticated)
+	}
+
+	if key.RevokedAt != "" {
+		return nil, fmt.Errorf("APIKeyUseCase - Authenticate: key %s is revoked: %w", prefix, entity.ErrUnauthenticated)
+	}
+
+	a.usage.add(key.Id, time.Now())
+
+	return key, nil
+}
+
+// FlushAPIKeyUsage saves the last use of the keys used since the last flush
+// and returns how many keys. Uses that fail to save are kept for the next flush.
+func (a *APIKeyUseCase) FlushAPIKeyUsage(ctx context.Context) (int64, error) {
+	used := a.usage.take()
+	if len(used) == 0 {
+		return 0, nil
+	}
+
+	if err := a.repo.TouchLastUsed(ctx, used); err != nil {
+		a.usage.putBack(used)
+
+		return 0, fmt.Errorf("APIKeyUseCase - FlushAPIKeyUsage - a.repo: %w", err)
+	}
+
+	return int64(len(used)), nil
+}
+
+func (b *usageBuffer) add(id string, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if at.After(b.pending[id]) {
+		b.pending[id] = at
+	}
+}
+
+func (b *usageBuffer) take() map[string]time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	used := b.pending
+	b.pending = make(map[string]time.Time, len(used))
+
+	return used
+}
+
+func (b *usageBuffer) putBack(used map[string]time.Time) {
+	for id, at := range used {
+		b.add(id, at)
+	}
+}
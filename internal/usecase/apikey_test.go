@@ -0,0 +1,99 @@
+package usecase_test
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockAPIKeyRepo(mockCtl)
+	keys := usecase.NewAPIKey(repo)
+
+	var minted *entity.APIKey
+	repo.EXPECT().Create(context.Background(), gomock.Any()).DoAndReturn(func(_ context.Context, req *entity.APIKey) (*entity.APIKey, error) {
+		minted = &entity.APIKey{}
+		*minted = *req
+		minted.Id = "key-id"
+
+		return minted, nil
+	})
+
+	key, token, err := keys.MintAPIKey(context.Background(), &entity.APIKey{
+		Name:      "importer",
+		Scopes:    []string{entity.ScopePostsWrite},
+		RateLimit: 600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "key-id", key.Id)
+	require.True(t, strings.HasPrefix(token, "psk_"+key.Prefix+"_"))
+	require.NotContains(t, string(key.Hash), token)
+
+	repo.EXPECT().GetByPrefix(context.Background(), key.Prefix).DoAndReturn(func(context.Context, string) (*entity.APIKey, error) {
+		return minted, nil
+	}).Times(3)
+
+	res, err := keys.AuthenticateAPIKey(context.Background(), token)
+	require.NoError(t, err)
+	require.Equal(t, "key-id", res.Id)
+
+	_, err = keys.AuthenticateAPIKey(context.Background(), token+"x")
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	_, err = keys.AuthenticateAPIKey(context.Background(), "not-a-key")
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	minted.RevokedAt = time.Now().String()
+
+	_, err = keys.AuthenticateAPIKey(context.Background(), token)
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	repo.EXPECT().GetByPrefix(context.Background(), "0123456789abcdef").Return(nil, entity.ErrNotFound)
+
+	_, err = keys.AuthenticateAPIKey(context.Background(), "psk_0123456789abcdef_secret")
+	require.ErrorIs(t, err, entity.ErrUnauthenticated)
+
+	repo.EXPECT().TouchLastUsed(context.Background(), gomock.Len(1)).Return(errInternalServerErr)
+
+	_, err = keys.FlushAPIKeyUsage(context.Background())
+	require.ErrorIs(t, err, errInternalServerErr)
+
+	repo.EXPECT().TouchLastUsed(context.Background(), gomock.Len(1)).Return(nil)
+
+	flushed, err := keys.FlushAPIKeyUsage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), flushed, "uses kept after a failed flush")
+
+	flushed, err = keys.FlushAPIKeyUsage(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, flushed)
+}
+
+func TestMintAPIKeyInvalid(t *testing.T) {
+	t.Parallel()
+
+	keys := usecase.NewAPIKey(NewMockAPIKeyRepo(gomock.NewController(t)))
+
+	_, _, err := keys.MintAPIKey(context.Background(), &entity.APIKey{
+		Scopes:    []string{"posts:everything"},
+		RateLimit: -1,
+	})
+
+	var validationErr *entity.ValidationError
+
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, []entity.FieldError{
+		{Field: "name", Code: entity.CodeRequired},
+		{Field: "scopes", Code: entity.CodeInvalidChoice},
+		{Field: "rate_limit", Code: entity.CodeOutOfRange},
+	}, validationErr.Errors)
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+)
+
+// CommentUseCase -.
+type CommentUseCase struct {
+	repo  CommentRepo
+	authz CommentAuthorizer
+}
+
+// NewComment -.
+func NewComment(r CommentRepo) *CommentUseCase {
+	return &CommentUseCase{repo: r, authz: RolePolicy{}}
+}
+
+// Create Comment adds a comment, or a reply when ParentId is set
+func (c *CommentUseCase) CreateComment(ctx context.Context, req *entity.Comment) (*entity.Comment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("CommentUseCase - Create - req.Validate: %w", err)
+	}
+
+	comment, err := c.repo.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("CommentUseCase - Create - c.repo: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Get Comment
+func (c *CommentUseCase) GetComment(ctx context.Context, postId, id string) (*entity.Comment, error) {
+	comment, err := c.repo.Get(ctx, postId, id)
+	if err != nil {
+		return nil, fmt.Errorf("CommentUseCase - Get - c.repo: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Update Comment changes the content of a comment, which its author, the API
+// key that wrote it, moderators and admins may do
+func (c *CommentUseCase) UpdateComment(ctx context.Context, req *entity.Comment) (*entity.Comment, error) {
+	if err := req.ValidateEdit(); err != nil {
+		return nil, fmt.Errorf("CommentUseCase - Update - req.ValidateEdit: %w", err)
+	}
+
+	if err := c.authorize(ctx, entity.ActionEditComment, req.PostId, req.Id); err != nil {
+		return nil, fmt.Errorf("CommentUseCase - Update - c.authorize: %w", err)
+	}
+
+	comment, err := c.repo.Update(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("CommentUseCase - Update - c.repo: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Delete Comment removes a comment together with its replies, which its
+// author, the API key that wrote it and admins may do
+func (c *CommentUseCase) DeleteComment(ctx context.Context, postId, id string) error {
+	if err := c.authorize(ctx, entity.ActionDeleteComment, postId, id); err != nil {
+		return fmt.Errorf("CommentUseCase - Delete - c.authorize: %w", err)
+	}
+
+	if err := c.repo.Delete(ctx, postId, id); err != nil {
+		return fmt.Errorf("CommentUseCase - Delete - c.repo: %w", err)
+	}
+
+	return nil
+}
+
+// List Comments of a post
+func (c *CommentUseCase) ListComments(ctx context.Context, req *entity.CommentFilter) (*entity.Comments, error) {
+	comments, err := c.repo.List(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("CommentUseCase - List - c.repo: %w", err)
+	}
+
+	return comments, nil
+}
+
+// authorize loads the comment id of the post and checks the caller in ctx may
+// perform action on it.
+func (c *CommentUseCase) authorize(ctx context.Context, action, postId, id string) error {
+	comment, err := c.repo.Get(ctx, postId, id)
+	if err != nil {
+		return fmt.Errorf("c.repo.Get: %w", err)
+	}
+
+	principal, _ := entity.PrincipalFromContext(ctx)
+
+	return c.authz.AuthorizeComment(ctx, principal, action, comment)
+}
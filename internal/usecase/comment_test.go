@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func comment(t *testing.T) (*usecase.CommentUseCase, *MockCommentRepo) {
+	t.Helper()
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	repo := NewMockCommentRepo(mockCtl)
+
+	return usecase.NewComment(repo), repo
+}
+
+func TestCreateComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reply", func(t *testing.T) {
+		t.Parallel()
+
+		comment, repo := comment(t)
+
+		req := &entity.Comment{
+			PostId:   "post-id",
+			ParentId: "5f0c8a8e-6a3c-4b8e-9c55-0d6f3f0c1a2b",
+			UserId:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+			Content:  "Nice post",
+		}
+		repo.EXPECT().Create(context.Background(), req).Return(req, nil)
+
+		res, err := comment.CreateComment(context.Background(), req)
+
+		require.NoError(t, err)
+		require.Equal(t, req, res)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		comment, _ := comment(t)
+
+		res, err := comment.CreateComment(context.Background(), &entity.Comment{
+			PostId:   "post-id",
+			ParentId: "not-a-uuid",
+		})
+
+		var validationErr *entity.ValidationError
+
+		require.Nil(t, res)
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []entity.FieldError{
+			{Field: "user_id", Code: entity.CodeRequired},
+			{Field: "parent_id", Code: entity.CodeInvalidUUID},
+			{Field: "content", Code: entity.CodeRequired},
+		}, validationErr.Errors)
+	})
+}
+
+func TestUpdateComment(t *testing.T) {
+	t.Parallel()
+
+	comment, _ := comment(t)
+
+	res, err := comment.UpdateComment(context.Background(), &entity.Comment{Id: "comment-id", PostId: "post-id"})
+
+	require.Nil(t, res)
+	require.ErrorIs(t, err, entity.ErrValidation)
+}
+
+func TestDeleteComment(t *testing.T) {
+	t.Parallel()
+
+	comment, repo := comment(t)
+
+	repo.EXPECT().Get(context.Background(), "post-id", "comment-id").Return(nil, entity.ErrNotFound)
+
+	err := comment.DeleteComment(context.Background(), "post-id", "comment-id")
+
+	require.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func TestCommentAuthorization(t *testing.T) {
+	t.Parallel()
+
+	comment, repo := comment(t)
+
+	as := func(p *entity.Principal) context.Context {
+		return entity.ContextWithPrincipal(context.Background(), p)
+	}
+
+	var (
+		author    = as(&entity.Principal{UserId: authorId})
+		other     = as(&entity.Principal{UserId: otherId})
+		moderator = as(&entity.Principal{UserId: otherId, Roles: []string{entity.RoleModerator}})
+		edit      = &entity.Comment{Id: "comment-id", PostId: "post-id", Content: "Edited"}
+	)
+
+	repo.EXPECT().Get(gomock.Any(), "post-id", "comment-id").
+		Return(&entity.Comment{Id: "comment-id", PostId: "post-id", UserId: authorId}, nil).AnyTimes()
+
+	_, err := comment.UpdateComment(other, edit)
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	err = comment.DeleteComment(other, "post-id", "comment-id")
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	err = comment.DeleteComment(moderator, "post-id", "comment-id")
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	_, err = comment.UpdateComment(context.Background(), edit)
+	require.ErrorIs(t, err, entity.ErrForbidden, "anonymous callers can't edit")
+
+	repo.EXPECT().Update(moderator, edit).Return(edit, nil)
+
+	_, err = comment.UpdateComment(moderator, edit)
+	require.NoError(t, err)
+
+	repo.EXPECT().Delete(author, "post-id", "comment-id").Return(nil)
+
+	require.NoError(t, comment.DeleteComment(author, "post-id", "comment-id"))
+}
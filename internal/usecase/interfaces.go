@@ -3,26 +3,118 @@ package usecase
 import (
 	"context"
 	"fourth-exam/post-service-clean-arch/internal/entity"
+	"time"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=./mocks_test.go -package=usecase_test
 
 type (
-	// Post -. 
+	// Post -.
 	Post interface {
 		CreatePost(context.Context, *entity.Post) (*entity.Post, error)
-		GetPost(context.Context, string) (*entity.Post, error) 
+		GetPost(context.Context, string) (*entity.Post, error)
+		ViewPost(ctx context.Context, id, viewer string) (*entity.Post, error)
+		FlushViews(context.Context) (int64, error)
 		UpdatePost(context.Context, *entity.Post) (*entity.Post, error)
-		DeletePost(context.Context, string) (error) 
+		PatchPost(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error)
+		DeletePost(ctx context.Context, id string, version int64) error
+		RestorePost(context.Context, string) (*entity.Post, error)
+		PurgeDeletedPosts(context.Context, time.Duration) (int64, error)
+		PublishPost(ctx context.Context, id string, at time.Time) (*entity.Post, error)
+		UnpublishPost(context.Context, string) (*entity.Post, error)
+		ArchivePost(context.Context, string) (*entity.Post, error)
+		PublishScheduledPosts(context.Context) (int64, error)
 		ListPosts(context.Context, *entity.GetListFilter) (*entity.Posts, error)
+		ListPostsByCursor(context.Context, *entity.GetListFilter) (*entity.Posts, error)
+		SearchPosts(context.Context, *entity.SearchFilter) (*entity.SearchResults, error)
+		React(context.Context, *entity.Reaction) (*entity.Post, error)
+		Unreact(context.Context, *entity.Reaction) (*entity.Post, error)
+		ListTags(context.Context, *entity.TagFilter) (*entity.Tags, error)
+		ListRevisions(context.Context, *entity.RevisionFilter) (*entity.Revisions, error)
+		GetRevision(ctx context.Context, postId string, version int64) (*entity.Revision, error)
+		DiffRevisions(ctx context.Context, postId string, from, to int64) (*entity.RevisionDiff, error)
+		RevertPost(ctx context.Context, id string, revision, version int64) (*entity.Post, error)
 	}
 
-	// PostRepo -. 
+	// PostRepo -.
 	PostRepo interface {
 		Create(context.Context, *entity.Post) (*entity.Post, error)
-		Get(context.Context, string) (*entity.Post, error) 
+		Get(context.Context, string) (*entity.Post, error)
 		Update(context.Context, *entity.Post) (*entity.Post, error)
-		Delete(context.Context, string) (error) 
+		Patch(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error)
+		Delete(ctx context.Context, id string, version int64) error
+		Restore(context.Context, string) error
+		Purge(ctx context.Context, retention time.Duration) (int64, error)
+		Transition(ctx context.Context, id string, change *entity.StatusChange) (*entity.Post, error)
+		PublishDue(ctx context.Context, now time.Time) (int64, error)
 		List(context.Context, *entity.GetListFilter) (*entity.Posts, error)
+		ListByCursor(context.Context, *entity.GetListFilter) (*entity.Posts, error)
+		Search(context.Context, *entity.SearchFilter) (*entity.SearchResults, error)
+		React(context.Context, *entity.Reaction) error
+		Unreact(ctx context.Context, postId, userId string) error
+		AddViews(ctx context.Context, views map[string]int64) error
+		ListTags(context.Context, *entity.TagFilter) (*entity.Tags, error)
+		ListRevisions(context.Context, *entity.RevisionFilter) (*entity.Revisions, error)
+		GetRevision(ctx context.Context, postId string, version int64) (*entity.Revision, error)
 	}
-)
\ No newline at end of file
+
+	// Comment -.
+	Comment interface {
+		CreateComment(context.Context, *entity.Comment) (*entity.Comment, error)
+		GetComment(ctx context.Context, postId, id string) (*entity.Comment, error)
+		UpdateComment(context.Context, *entity.Comment) (*entity.Comment, error)
+		DeleteComment(ctx context.Context, postId, id string) error
+		ListComments(context.Context, *entity.CommentFilter) (*entity.Comments, error)
+	}
+
+	// CommentRepo -.
+	CommentRepo interface {
+		Create(context.Context, *entity.Comment) (*entity.Comment, error)
+		Get(ctx context.Context, postId, id string) (*entity.Comment, error)
+		Update(context.Context, *entity.Comment) (*entity.Comment, error)
+		Delete(ctx context.Context, postId, id string) error
+		List(context.Context, *entity.CommentFilter) (*entity.Comments, error)
+	}
+
+	// APIKey -.
+	APIKey interface {
+		MintAPIKey(context.Context, *entity.APIKey) (key *entity.APIKey, token string, err error)
+		RevokeAPIKey(ctx context.Context, id string) error
+		ListAPIKeys(context.Context) (*entity.APIKeys, error)
+		AuthenticateAPIKey(ctx context.Context, token string) (*entity.APIKey, error)
+		FlushAPIKeyUsage(context.Context) (int64, error)
+	}
+
+	// APIKeyRepo -.
+	APIKeyRepo interface {
+		Create(context.Context, *entity.APIKey) (*entity.APIKey, error)
+		GetByPrefix(context.Context, string) (*entity.APIKey, error)
+		List(context.Context) (*entity.APIKeys, error)
+		Revoke(ctx context.Context, id string) error
+		TouchLastUsed(ctx context.Context, used map[string]time.Time) error
+	}
+
+	// Authorizer decides whether a principal, nil for anonymous callers, may
+	// perform an action on a post. It returns an error wrapping
+	// entity.ErrForbidden when it may not. Listing actions get a post
+	// carrying only the author the listing is limited to, if any.
+	Authorizer interface {
+		Authorize(ctx context.Context, p *entity.Principal, action string, post *entity.Post) error
+	}
+
+	// CommentAuthorizer is Authorizer for actions on a comment.
+	CommentAuthorizer interface {
+		AuthorizeComment(ctx context.Context, p *entity.Principal, action string, comment *entity.Comment) error
+	}
+
+	// TxManager -.
+	TxManager interface {
+		WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
+	// Cursor -.
+	Cursor interface {
+		Encode(interface{}) (string, error)
+		Decode(string, interface{}) error
+	}
+)
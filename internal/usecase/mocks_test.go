@@ -8,6 +8,7 @@ import (
 	context "context"
 	entity "fourth-exam/post-service-clean-arch/internal/entity"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockPost) EXPECT() *MockPostMockRecorder {
 	return m.recorder
 }
 
+// ArchivePost mocks base method.
+func (m *MockPost) ArchivePost(arg0 context.Context, arg1 string) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchivePost", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchivePost indicates an expected call of ArchivePost.
+func (mr *MockPostMockRecorder) ArchivePost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchivePost", reflect.TypeOf((*MockPost)(nil).ArchivePost), arg0, arg1)
+}
+
 // CreatePost mocks base method.
 func (m *MockPost) CreatePost(arg0 context.Context, arg1 *entity.Post) (*entity.Post, error) {
 	m.ctrl.T.Helper()
@@ -51,17 +67,47 @@ func (mr *MockPostMockRecorder) CreatePost(arg0, arg1 interface{}) *gomock.Call
 }
 
 // DeletePost mocks base method.
-func (m *MockPost) DeletePost(arg0 context.Context, arg1 string) error {
+func (m *MockPost) DeletePost(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeletePost", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePost indicates an expected call of DeletePost.
-func (mr *MockPostMockRecorder) DeletePost(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockPostMockRecorder) DeletePost(ctx, id, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPost)(nil).DeletePost), ctx, id, version)
+}
+
+// DiffRevisions mocks base method.
+func (m *MockPost) DiffRevisions(ctx context.Context, postId string, from, to int64) (*entity.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffRevisions", ctx, postId, from, to)
+	ret0, _ := ret[0].(*entity.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffRevisions indicates an expected call of DiffRevisions.
+func (mr *MockPostMockRecorder) DiffRevisions(ctx, postId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffRevisions", reflect.TypeOf((*MockPost)(nil).DiffRevisions), ctx, postId, from, to)
+}
+
+// FlushViews mocks base method.
+func (m *MockPost) FlushViews(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushViews", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlushViews indicates an expected call of FlushViews.
+func (mr *MockPostMockRecorder) FlushViews(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockPost)(nil).DeletePost), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushViews", reflect.TypeOf((*MockPost)(nil).FlushViews), arg0)
 }
 
 // GetPost mocks base method.
@@ -79,6 +125,21 @@ func (mr *MockPostMockRecorder) GetPost(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPost", reflect.TypeOf((*MockPost)(nil).GetPost), arg0, arg1)
 }
 
+// GetRevision mocks base method.
+func (m *MockPost) GetRevision(ctx context.Context, postId string, version int64) (*entity.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, postId, version)
+	ret0, _ := ret[0].(*entity.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockPostMockRecorder) GetRevision(ctx, postId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockPost)(nil).GetRevision), ctx, postId, version)
+}
+
 // ListPosts mocks base method.
 func (m *MockPost) ListPosts(arg0 context.Context, arg1 *entity.GetListFilter) (*entity.Posts, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +155,201 @@ func (mr *MockPostMockRecorder) ListPosts(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPosts", reflect.TypeOf((*MockPost)(nil).ListPosts), arg0, arg1)
 }
 
+// ListPostsByCursor mocks base method.
+func (m *MockPost) ListPostsByCursor(arg0 context.Context, arg1 *entity.GetListFilter) (*entity.Posts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPostsByCursor", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Posts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPostsByCursor indicates an expected call of ListPostsByCursor.
+func (mr *MockPostMockRecorder) ListPostsByCursor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPostsByCursor", reflect.TypeOf((*MockPost)(nil).ListPostsByCursor), arg0, arg1)
+}
+
+// ListRevisions mocks base method.
+func (m *MockPost) ListRevisions(arg0 context.Context, arg1 *entity.RevisionFilter) (*entity.Revisions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Revisions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockPostMockRecorder) ListRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockPost)(nil).ListRevisions), arg0, arg1)
+}
+
+// ListTags mocks base method.
+func (m *MockPost) ListTags(arg0 context.Context, arg1 *entity.TagFilter) (*entity.Tags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Tags)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockPostMockRecorder) ListTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockPost)(nil).ListTags), arg0, arg1)
+}
+
+// PatchPost mocks base method.
+func (m *MockPost) PatchPost(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPost", ctx, id, patch)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchPost indicates an expected call of PatchPost.
+func (mr *MockPostMockRecorder) PatchPost(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPost", reflect.TypeOf((*MockPost)(nil).PatchPost), ctx, id, patch)
+}
+
+// PublishPost mocks base method.
+func (m *MockPost) PublishPost(ctx context.Context, id string, at time.Time) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishPost", ctx, id, at)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishPost indicates an expected call of PublishPost.
+func (mr *MockPostMockRecorder) PublishPost(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishPost", reflect.TypeOf((*MockPost)(nil).PublishPost), ctx, id, at)
+}
+
+// PublishScheduledPosts mocks base method.
+func (m *MockPost) PublishScheduledPosts(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduledPosts", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishScheduledPosts indicates an expected call of PublishScheduledPosts.
+func (mr *MockPostMockRecorder) PublishScheduledPosts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduledPosts", reflect.TypeOf((*MockPost)(nil).PublishScheduledPosts), arg0)
+}
+
+// PurgeDeletedPosts mocks base method.
+func (m *MockPost) PurgeDeletedPosts(arg0 context.Context, arg1 time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedPosts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedPosts indicates an expected call of PurgeDeletedPosts.
+func (mr *MockPostMockRecorder) PurgeDeletedPosts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedPosts", reflect.TypeOf((*MockPost)(nil).PurgeDeletedPosts), arg0, arg1)
+}
+
+// React mocks base method.
+func (m *MockPost) React(arg0 context.Context, arg1 *entity.Reaction) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// React indicates an expected call of React.
+func (mr *MockPostMockRecorder) React(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*MockPost)(nil).React), arg0, arg1)
+}
+
+// RestorePost mocks base method.
+func (m *MockPost) RestorePost(arg0 context.Context, arg1 string) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePost", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestorePost indicates an expected call of RestorePost.
+func (mr *MockPostMockRecorder) RestorePost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePost", reflect.TypeOf((*MockPost)(nil).RestorePost), arg0, arg1)
+}
+
+// RevertPost mocks base method.
+func (m *MockPost) RevertPost(ctx context.Context, id string, revision, version int64) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertPost", ctx, id, revision, version)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertPost indicates an expected call of RevertPost.
+func (mr *MockPostMockRecorder) RevertPost(ctx, id, revision, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertPost", reflect.TypeOf((*MockPost)(nil).RevertPost), ctx, id, revision, version)
+}
+
+// SearchPosts mocks base method.
+func (m *MockPost) SearchPosts(arg0 context.Context, arg1 *entity.SearchFilter) (*entity.SearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPosts", arg0, arg1)
+	ret0, _ := ret[0].(*entity.SearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPosts indicates an expected call of SearchPosts.
+func (mr *MockPostMockRecorder) SearchPosts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPosts", reflect.TypeOf((*MockPost)(nil).SearchPosts), arg0, arg1)
+}
+
+// UnpublishPost mocks base method.
+func (m *MockPost) UnpublishPost(arg0 context.Context, arg1 string) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpublishPost", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpublishPost indicates an expected call of UnpublishPost.
+func (mr *MockPostMockRecorder) UnpublishPost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishPost", reflect.TypeOf((*MockPost)(nil).UnpublishPost), arg0, arg1)
+}
+
+// Unreact mocks base method.
+func (m *MockPost) Unreact(arg0 context.Context, arg1 *entity.Reaction) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unreact indicates an expected call of Unreact.
+func (mr *MockPostMockRecorder) Unreact(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*MockPost)(nil).Unreact), arg0, arg1)
+}
+
 // UpdatePost mocks base method.
 func (m *MockPost) UpdatePost(arg0 context.Context, arg1 *entity.Post) (*entity.Post, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +365,21 @@ func (mr *MockPostMockRecorder) UpdatePost(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockPost)(nil).UpdatePost), arg0, arg1)
 }
 
+// ViewPost mocks base method.
+func (m *MockPost) ViewPost(ctx context.Context, id, viewer string) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewPost", ctx, id, viewer)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewPost indicates an expected call of ViewPost.
+func (mr *MockPostMockRecorder) ViewPost(ctx, id, viewer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewPost", reflect.TypeOf((*MockPost)(nil).ViewPost), ctx, id, viewer)
+}
+
 // MockPostRepo is a mock of PostRepo interface.
 type MockPostRepo struct {
 	ctrl     *gomock.Controller
@@ -132,6 +403,20 @@ func (m *MockPostRepo) EXPECT() *MockPostRepoMockRecorder {
 	return m.recorder
 }
 
+// AddViews mocks base method.
+func (m *MockPostRepo) AddViews(ctx context.Context, views map[string]int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddViews", ctx, views)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddViews indicates an expected call of AddViews.
+func (mr *MockPostRepoMockRecorder) AddViews(ctx, views interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViews", reflect.TypeOf((*MockPostRepo)(nil).AddViews), ctx, views)
+}
+
 // Create mocks base method.
 func (m *MockPostRepo) Create(arg0 context.Context, arg1 *entity.Post) (*entity.Post, error) {
 	m.ctrl.T.Helper()
@@ -148,17 +433,17 @@ func (mr *MockPostRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MockPostRepo) Delete(arg0 context.Context, arg1 string) error {
+func (m *MockPostRepo) Delete(ctx context.Context, id string, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockPostRepoMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockPostRepoMockRecorder) Delete(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPostRepo)(nil).Delete), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPostRepo)(nil).Delete), ctx, id, version)
 }
 
 // Get mocks base method.
@@ -176,6 +461,21 @@ func (mr *MockPostRepoMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPostRepo)(nil).Get), arg0, arg1)
 }
 
+// GetRevision mocks base method.
+func (m *MockPostRepo) GetRevision(ctx context.Context, postId string, version int64) (*entity.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, postId, version)
+	ret0, _ := ret[0].(*entity.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockPostRepoMockRecorder) GetRevision(ctx, postId, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockPostRepo)(nil).GetRevision), ctx, postId, version)
+}
+
 // List mocks base method.
 func (m *MockPostRepo) List(arg0 context.Context, arg1 *entity.GetListFilter) (*entity.Posts, error) {
 	m.ctrl.T.Helper()
@@ -191,17 +491,730 @@ func (mr *MockPostRepoMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPostRepo)(nil).List), arg0, arg1)
 }
 
-// Update mocks base method.
-func (m *MockPostRepo) Update(arg0 context.Context, arg1 *entity.Post) (*entity.Post, error) {
+// ListByCursor mocks base method.
+func (m *MockPostRepo) ListByCursor(arg0 context.Context, arg1 *entity.GetListFilter) (*entity.Posts, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret := m.ctrl.Call(m, "ListByCursor", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Posts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCursor indicates an expected call of ListByCursor.
+func (mr *MockPostRepoMockRecorder) ListByCursor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCursor", reflect.TypeOf((*MockPostRepo)(nil).ListByCursor), arg0, arg1)
+}
+
+// ListRevisions mocks base method.
+func (m *MockPostRepo) ListRevisions(arg0 context.Context, arg1 *entity.RevisionFilter) (*entity.Revisions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Revisions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockPostRepoMockRecorder) ListRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockPostRepo)(nil).ListRevisions), arg0, arg1)
+}
+
+// ListTags mocks base method.
+func (m *MockPostRepo) ListTags(arg0 context.Context, arg1 *entity.TagFilter) (*entity.Tags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTags", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Tags)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTags indicates an expected call of ListTags.
+func (mr *MockPostRepoMockRecorder) ListTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTags", reflect.TypeOf((*MockPostRepo)(nil).ListTags), arg0, arg1)
+}
+
+// Patch mocks base method.
+func (m *MockPostRepo) Patch(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", ctx, id, patch)
 	ret0, _ := ret[0].(*entity.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockPostRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+// Patch indicates an expected call of Patch.
+func (mr *MockPostRepoMockRecorder) Patch(ctx, id, patch interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPostRepo)(nil).Update), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockPostRepo)(nil).Patch), ctx, id, patch)
+}
+
+// PublishDue mocks base method.
+func (m *MockPostRepo) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue.
+func (mr *MockPostRepoMockRecorder) PublishDue(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockPostRepo)(nil).PublishDue), ctx, now)
+}
+
+// Purge mocks base method.
+func (m *MockPostRepo) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockPostRepoMockRecorder) Purge(ctx, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockPostRepo)(nil).Purge), ctx, retention)
+}
+
+// React mocks base method.
+func (m *MockPostRepo) React(arg0 context.Context, arg1 *entity.Reaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// React indicates an expected call of React.
+func (mr *MockPostRepoMockRecorder) React(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*MockPostRepo)(nil).React), arg0, arg1)
+}
+
+// Restore mocks base method.
+func (m *MockPostRepo) Restore(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockPostRepoMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockPostRepo)(nil).Restore), arg0, arg1)
+}
+
+// Search mocks base method.
+func (m *MockPostRepo) Search(arg0 context.Context, arg1 *entity.SearchFilter) (*entity.SearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", arg0, arg1)
+	ret0, _ := ret[0].(*entity.SearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockPostRepoMockRecorder) Search(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockPostRepo)(nil).Search), arg0, arg1)
+}
+
+// Transition mocks base method.
+func (m *MockPostRepo) Transition(ctx context.Context, id string, change *entity.StatusChange) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, id, change)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockPostRepoMockRecorder) Transition(ctx, id, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockPostRepo)(nil).Transition), ctx, id, change)
+}
+
+// Unreact mocks base method.
+func (m *MockPostRepo) Unreact(ctx context.Context, postId, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", ctx, postId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unreact indicates an expected call of Unreact.
+func (mr *MockPostRepoMockRecorder) Unreact(ctx, postId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*MockPostRepo)(nil).Unreact), ctx, postId, userId)
+}
+
+// Update mocks base method.
+func (m *MockPostRepo) Update(arg0 context.Context, arg1 *entity.Post) (*entity.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPostRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPostRepo)(nil).Update), arg0, arg1)
+}
+
+// MockComment is a mock of Comment interface.
+type MockComment struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentMockRecorder
+}
+
+// MockCommentMockRecorder is the mock recorder for MockComment.
+type MockCommentMockRecorder struct {
+	mock *MockComment
+}
+
+// NewMockComment creates a new mock instance.
+func NewMockComment(ctrl *gomock.Controller) *MockComment {
+	mock := &MockComment{ctrl: ctrl}
+	mock.recorder = &MockCommentMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockComment) EXPECT() *MockCommentMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockComment) CreateComment(arg0 context.Context, arg1 *entity.Comment) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockCommentMockRecorder) CreateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockComment)(nil).CreateComment), arg0, arg1)
+}
+
+// DeleteComment mocks base method.
+func (m *MockComment) DeleteComment(ctx context.Context, postId, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, postId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockCommentMockRecorder) DeleteComment(ctx, postId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockComment)(nil).DeleteComment), ctx, postId, id)
+}
+
+// GetComment mocks base method.
+func (m *MockComment) GetComment(ctx context.Context, postId, id string) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", ctx, postId, id)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockCommentMockRecorder) GetComment(ctx, postId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockComment)(nil).GetComment), ctx, postId, id)
+}
+
+// ListComments mocks base method.
+func (m *MockComment) ListComments(arg0 context.Context, arg1 *entity.CommentFilter) (*entity.Comments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComments", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Comments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComments indicates an expected call of ListComments.
+func (mr *MockCommentMockRecorder) ListComments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComments", reflect.TypeOf((*MockComment)(nil).ListComments), arg0, arg1)
+}
+
+// UpdateComment mocks base method.
+func (m *MockComment) UpdateComment(arg0 context.Context, arg1 *entity.Comment) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockCommentMockRecorder) UpdateComment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockComment)(nil).UpdateComment), arg0, arg1)
+}
+
+// MockCommentRepo is a mock of CommentRepo interface.
+type MockCommentRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentRepoMockRecorder
+}
+
+// MockCommentRepoMockRecorder is the mock recorder for MockCommentRepo.
+type MockCommentRepoMockRecorder struct {
+	mock *MockCommentRepo
+}
+
+// NewMockCommentRepo creates a new mock instance.
+func NewMockCommentRepo(ctrl *gomock.Controller) *MockCommentRepo {
+	mock := &MockCommentRepo{ctrl: ctrl}
+	mock.recorder = &MockCommentRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentRepo) EXPECT() *MockCommentRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCommentRepo) Create(arg0 context.Context, arg1 *entity.Comment) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCommentRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCommentRepo)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockCommentRepo) Delete(ctx context.Context, postId, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, postId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCommentRepoMockRecorder) Delete(ctx, postId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCommentRepo)(nil).Delete), ctx, postId, id)
+}
+
+// Get mocks base method.
+func (m *MockCommentRepo) Get(ctx context.Context, postId, id string) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, postId, id)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCommentRepoMockRecorder) Get(ctx, postId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCommentRepo)(nil).Get), ctx, postId, id)
+}
+
+// List mocks base method.
+func (m *MockCommentRepo) List(arg0 context.Context, arg1 *entity.CommentFilter) (*entity.Comments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Comments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockCommentRepoMockRecorder) List(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCommentRepo)(nil).List), arg0, arg1)
+}
+
+// Update mocks base method.
+func (m *MockCommentRepo) Update(arg0 context.Context, arg1 *entity.Comment) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCommentRepoMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCommentRepo)(nil).Update), arg0, arg1)
+}
+
+// MockAPIKey is a mock of APIKey interface.
+type MockAPIKey struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyMockRecorder
+}
+
+// MockAPIKeyMockRecorder is the mock recorder for MockAPIKey.
+type MockAPIKeyMockRecorder struct {
+	mock *MockAPIKey
+}
+
+// NewMockAPIKey creates a new mock instance.
+func NewMockAPIKey(ctrl *gomock.Controller) *MockAPIKey {
+	mock := &MockAPIKey{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKey) EXPECT() *MockAPIKeyMockRecorder {
+	return m.recorder
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockAPIKey) AuthenticateAPIKey(ctx context.Context, token string) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, token)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockAPIKeyMockRecorder) AuthenticateAPIKey(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockAPIKey)(nil).AuthenticateAPIKey), ctx, token)
+}
+
+// FlushAPIKeyUsage mocks base method.
+func (m *MockAPIKey) FlushAPIKeyUsage(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushAPIKeyUsage", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FlushAPIKeyUsage indicates an expected call of FlushAPIKeyUsage.
+func (mr *MockAPIKeyMockRecorder) FlushAPIKeyUsage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushAPIKeyUsage", reflect.TypeOf((*MockAPIKey)(nil).FlushAPIKeyUsage), arg0)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPIKey) ListAPIKeys(arg0 context.Context) (*entity.APIKeys, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", arg0)
+	ret0, _ := ret[0].(*entity.APIKeys)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIKeyMockRecorder) ListAPIKeys(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKey)(nil).ListAPIKeys), arg0)
+}
+
+// MintAPIKey mocks base method.
+func (m *MockAPIKey) MintAPIKey(arg0 context.Context, arg1 *entity.APIKey) (*entity.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MintAPIKey", arg0, arg1)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MintAPIKey indicates an expected call of MintAPIKey.
+func (mr *MockAPIKeyMockRecorder) MintAPIKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MintAPIKey", reflect.TypeOf((*MockAPIKey)(nil).MintAPIKey), arg0, arg1)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKey) RevokeAPIKey(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKey)(nil).RevokeAPIKey), ctx, id)
+}
+
+// MockAPIKeyRepo is a mock of APIKeyRepo interface.
+type MockAPIKeyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepoMockRecorder
+}
+
+// MockAPIKeyRepoMockRecorder is the mock recorder for MockAPIKeyRepo.
+type MockAPIKeyRepoMockRecorder struct {
+	mock *MockAPIKeyRepo
+}
+
+// NewMockAPIKeyRepo creates a new mock instance.
+func NewMockAPIKeyRepo(ctrl *gomock.Controller) *MockAPIKeyRepo {
+	mock := &MockAPIKeyRepo{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepo) EXPECT() *MockAPIKeyRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIKeyRepo) Create(arg0 context.Context, arg1 *entity.APIKey) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyRepoMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKeyRepo)(nil).Create), arg0, arg1)
+}
+
+// GetByPrefix mocks base method.
+func (m *MockAPIKeyRepo) GetByPrefix(arg0 context.Context, arg1 string) (*entity.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPrefix", arg0, arg1)
+	ret0, _ := ret[0].(*entity.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPrefix indicates an expected call of GetByPrefix.
+func (mr *MockAPIKeyRepoMockRecorder) GetByPrefix(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPrefix", reflect.TypeOf((*MockAPIKeyRepo)(nil).GetByPrefix), arg0, arg1)
+}
+
+// List mocks base method.
+func (m *MockAPIKeyRepo) List(arg0 context.Context) (*entity.APIKeys, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].(*entity.APIKeys)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAPIKeyRepoMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAPIKeyRepo)(nil).List), arg0)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeyRepo) Revoke(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyRepoMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeyRepo)(nil).Revoke), ctx, id)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockAPIKeyRepo) TouchLastUsed(ctx context.Context, used map[string]time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, used)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockAPIKeyRepoMockRecorder) TouchLastUsed(ctx, used interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockAPIKeyRepo)(nil).TouchLastUsed), ctx, used)
+}
+
+// MockAuthorizer is a mock of Authorizer interface.
+type MockAuthorizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizerMockRecorder
+}
+
+// MockAuthorizerMockRecorder is the mock recorder for MockAuthorizer.
+type MockAuthorizerMockRecorder struct {
+	mock *MockAuthorizer
+}
+
+// NewMockAuthorizer creates a new mock instance.
+func NewMockAuthorizer(ctrl *gomock.Controller) *MockAuthorizer {
+	mock := &MockAuthorizer{ctrl: ctrl}
+	mock.recorder = &MockAuthorizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizer) EXPECT() *MockAuthorizerMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockAuthorizer) Authorize(ctx context.Context, p *entity.Principal, action string, post *entity.Post) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, p, action, post)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockAuthorizerMockRecorder) Authorize(ctx, p, action, post interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAuthorizer)(nil).Authorize), ctx, p, action, post)
+}
+
+// MockCommentAuthorizer is a mock of CommentAuthorizer interface.
+type MockCommentAuthorizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockCommentAuthorizerMockRecorder
+}
+
+// MockCommentAuthorizerMockRecorder is the mock recorder for MockCommentAuthorizer.
+type MockCommentAuthorizerMockRecorder struct {
+	mock *MockCommentAuthorizer
+}
+
+// NewMockCommentAuthorizer creates a new mock instance.
+func NewMockCommentAuthorizer(ctrl *gomock.Controller) *MockCommentAuthorizer {
+	mock := &MockCommentAuthorizer{ctrl: ctrl}
+	mock.recorder = &MockCommentAuthorizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCommentAuthorizer) EXPECT() *MockCommentAuthorizerMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeComment mocks base method.
+func (m *MockCommentAuthorizer) AuthorizeComment(ctx context.Context, p *entity.Principal, action string, comment *entity.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeComment", ctx, p, action, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AuthorizeComment indicates an expected call of AuthorizeComment.
+func (mr *MockCommentAuthorizerMockRecorder) AuthorizeComment(ctx, p, action, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeComment", reflect.TypeOf((*MockCommentAuthorizer)(nil).AuthorizeComment), ctx, p, action, comment)
+}
+
+// MockTxManager is a mock of TxManager interface.
+type MockTxManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockTxManagerMockRecorder
+}
+
+// MockTxManagerMockRecorder is the mock recorder for MockTxManager.
+type MockTxManagerMockRecorder struct {
+	mock *MockTxManager
+}
+
+// NewMockTxManager creates a new mock instance.
+func NewMockTxManager(ctrl *gomock.Controller) *MockTxManager {
+	mock := &MockTxManager{ctrl: ctrl}
+	mock.recorder = &MockTxManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTxManager) EXPECT() *MockTxManagerMockRecorder {
+	return m.recorder
+}
+
+// WithinTx mocks base method.
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTx indicates an expected call of WithinTx.
+func (mr *MockTxManagerMockRecorder) WithinTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTx", reflect.TypeOf((*MockTxManager)(nil).WithinTx), ctx, fn)
+}
+
+// MockCursor is a mock of Cursor interface.
+type MockCursor struct {
+	ctrl     *gomock.Controller
+	recorder *MockCursorMockRecorder
+}
+
+// MockCursorMockRecorder is the mock recorder for MockCursor.
+type MockCursorMockRecorder struct {
+	mock *MockCursor
+}
+
+// NewMockCursor creates a new mock instance.
+func NewMockCursor(ctrl *gomock.Controller) *MockCursor {
+	mock := &MockCursor{ctrl: ctrl}
+	mock.recorder = &MockCursorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCursor) EXPECT() *MockCursorMockRecorder {
+	return m.recorder
+}
+
+// Decode mocks base method.
+func (m *MockCursor) Decode(arg0 string, arg1 interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decode indicates an expected call of Decode.
+func (mr *MockCursorMockRecorder) Decode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockCursor)(nil).Decode), arg0, arg1)
+}
+
+// Encode mocks base method.
+func (m *MockCursor) Encode(arg0 interface{}) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockCursorMockRecorder) Encode(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockCursor)(nil).Encode), arg0)
 }
@@ -0,0 +1,21 @@
+package usecase
+
+import "time"
+
+// Option -.
+type Option func(*PostUseCase)
+
+// ViewWindow sets how long a viewer's repeated views of a post count once.
+// Zero counts every view.
+func ViewWindow(window time.Duration) Option {
+	return func(p *PostUseCase) {
+		p.views.window = window
+	}
+}
+
+// Policy sets the Authorizer for post mutations, RolePolicy by default.
+func Policy(a Authorizer) Option {
+	return func(p *PostUseCase) {
+		p.authz = a
+	}
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+)
+
+// RolePolicy is the default Authorizer: authors see, edit and delete their
+// own posts, moderators see and edit any post and admins may do anything,
+// including listing, restoring and purging deleted posts. Authors and
+// moderators may list unpublished posts, authors only their own. API keys have
+// scopes rather than roles: keys see the posts they wrote, posts:write keys
+// edit and delete them, and admin keys may do anything. Comments follow the
+// same rules.
+type RolePolicy struct{}
+
+var (
+	_ Authorizer        = RolePolicy{}
+	_ CommentAuthorizer = RolePolicy{}
+)
+
+// Authorize -.
+func (RolePolicy) Authorize(_ context.Context, p *entity.Principal, action string, post *entity.Post) error {
+	if p == nil {
+		return fmt.Errorf("%w: %s needs an authenticated user", entity.ErrForbidden, action)
+	}
+
+	if p.APIKeyId != "" {
+		return authorizeAPIKey(p, action, post)
+	}
+
+	if p.HasRole(entity.RoleAdmin) {
+		return nil
+	}
+
+	owner := post != nil && p.UserId != "" && post.UserId == p.UserId
+
+	switch action {
+	case entity.ActionViewPost, entity.ActionEditPost, entity.ActionListUnpublishedPosts:
+		if owner || p.HasRole(entity.RoleModerator) {
+			return nil
+		}
+	case entity.ActionDeletePost:
+		if owner {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s not allowed", entity.ErrForbidden, action)
+}
+
+// AuthorizeComment -.
+func (RolePolicy) AuthorizeComment(_ context.Context, p *entity.Principal, action string, comment *entity.Comment) error {
+	if p == nil {
+		return fmt.Errorf("%w: %s needs an authenticated user", entity.ErrForbidden, action)
+	}
+
+	if p.HasRole(entity.RoleAdmin) || p.HasScope(entity.ScopeAdmin) {
+		return nil
+	}
+
+	var owner bool
+	if p.APIKeyId != "" {
+		owner = comment != nil && comment.APIKeyId == p.APIKeyId && p.HasScope(entity.ScopePostsWrite)
+	} else {
+		owner = comment != nil && p.UserId != "" && comment.UserId == p.UserId
+	}
+
+	switch action {
+	case entity.ActionEditComment:
+		if owner || p.HasRole(entity.RoleModerator) {
+			return nil
+		}
+	case entity.ActionDeleteComment:
+		if owner {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s not allowed", entity.ErrForbidden, action)
+}
+
+// authorizeAPIKey is RolePolicy for a principal calling with an API key.
+func authorizeAPIKey(p *entity.Principal, action string, post *entity.Post) error {
+	if p.HasScope(entity.ScopeAdmin) {
+		return nil
+	}
+
+	own := post != nil && post.APIKeyId == p.APIKeyId
+
+	switch action {
+	case entity.ActionViewPost:
+		if own && (p.HasScope(entity.ScopePostsRead) || p.HasScope(entity.ScopePostsWrite)) {
+			return nil
+		}
+	case entity.ActionEditPost, entity.ActionDeletePost:
+		if own && p.HasScope(entity.ScopePostsWrite) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s not allowed for api key", entity.ErrForbidden, action)
+}
+
+// authorize checks the caller in ctx may perform action on post, which is
+// nil for actions that aren't about a single post.
+func (p *PostUseCase) authorize(ctx context.Context, action string, post *entity.Post) error {
+	principal, _ := entity.PrincipalFromContext(ctx)
+
+	return p.authz.Authorize(ctx, principal, action, post)
+}
+
+// viewablePost loads the post id, which callers that may not see it get as
+// not found unless it is published.
+func (p *PostUseCase) viewablePost(ctx context.Context, id string) (*entity.Post, error) {
+	post, err := p.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("p.repo.Get: %w", err)
+	}
+
+	if post.Status == entity.StatusPublished {
+		return post, nil
+	}
+
+	if err := p.authorize(ctx, entity.ActionViewPost, post); err != nil {
+		return nil, fmt.Errorf("%s post: %w", post.Status, entity.ErrNotFound)
+	}
+
+	return post, nil
+}
+
+// authorizeList checks the caller in ctx may list posts in the statuses req
+// asks for, which are all of them when it asks for none, and deleted posts if
+// it asks for them too.
+func (p *PostUseCase) authorizeList(ctx context.Context, req *entity.GetListFilter) error {
+	if req.IncludeDeleted {
+		if err := p.authorize(ctx, entity.ActionListDeletedPosts, nil); err != nil {
+			return err
+		}
+	}
+
+	published := len(req.Statuses) > 0
+	for _, status := range req.Statuses {
+		published = published && status == entity.StatusPublished
+	}
+
+	if published {
+		return nil
+	}
+
+	return p.authorize(ctx, entity.ActionListUnpublishedPosts, &entity.Post{UserId: req.UserId})
+}
+
+// authorizePost loads the post id and checks the caller in ctx may perform
+// action on it.
+func (p *PostUseCase) authorizePost(ctx context.Context, action, id string) (*entity.Post, error) {
+	post, err := p.repo.Get(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("p.repo.Get: %w", err)
+	}
+
+	if err := p.authorize(ctx, action, post); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
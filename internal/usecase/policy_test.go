@@ -0,0 +1,365 @@
+package usecase_test
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/cursor"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	authorId = "d0b69f3b-2021-4d91-8e13-c243d9eb5292"
+	otherId  = "a1b69f3b-2021-4d91-8e13-c243d9eb5292"
+	postId   = "b0b69f3b-2021-4d91-8e13-c243d9eb5292"
+	keyId    = "c0b69f3b-2021-4d91-8e13-c243d9eb5292"
+)
+
+func TestRolePolicy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		post = &entity.Post{Id: postId, UserId: authorId, APIKeyId: keyId}
+
+		anonymous = (*entity.Principal)(nil)
+		author    = &entity.Principal{UserId: authorId}
+		other     = &entity.Principal{UserId: otherId}
+		moderator = &entity.Principal{UserId: otherId, Roles: []string{entity.RoleModerator}}
+		admin     = &entity.Principal{UserId: otherId, Roles: []string{entity.RoleAdmin}}
+		noUser    = &entity.Principal{}
+
+		writerKey = &entity.Principal{APIKeyId: keyId, Scopes: []string{entity.ScopePostsWrite}}
+		readerKey = &entity.Principal{APIKeyId: keyId, Scopes: []string{entity.ScopePostsRead}}
+		otherKey  = &entity.Principal{APIKeyId: otherId, Scopes: []string{entity.ScopePostsWrite}}
+		adminKey  = &entity.Principal{APIKeyId: otherId, Scopes: []string{entity.ScopeAdmin}}
+	)
+
+	tests := []struct {
+		name      string
+		principal *entity.Principal
+		allowed   map[string]bool
+	}{
+		{"anonymous", anonymous, nil},
+		{"author", author, map[string]bool{
+			entity.ActionViewPost:             true,
+			entity.ActionEditPost:             true,
+			entity.ActionDeletePost:           true,
+			entity.ActionListUnpublishedPosts: true,
+		}},
+		{"other user", other, nil},
+		{"moderator", moderator, map[string]bool{
+			entity.ActionViewPost:             true,
+			entity.ActionEditPost:             true,
+			entity.ActionListUnpublishedPosts: true,
+		}},
+		{"admin", admin, map[string]bool{
+			entity.ActionViewPost:             true,
+			entity.ActionEditPost:             true,
+			entity.ActionDeletePost:           true,
+			entity.ActionRestorePost:          true,
+			entity.ActionPurgePosts:           true,
+			entity.ActionListUnpublishedPosts: true,
+			entity.ActionListDeletedPosts:     true,
+		}},
+		{"principal without user", noUser, nil},
+		{"key that wrote the post", writerKey, map[string]bool{
+			entity.ActionViewPost:   true,
+			entity.ActionEditPost:   true,
+			entity.ActionDeletePost: true,
+		}},
+		{"read-only key that wrote the post", readerKey, map[string]bool{entity.ActionViewPost: true}},
+		{"other key", otherKey, nil},
+		{"admin key", adminKey, map[string]bool{
+			entity.ActionViewPost:             true,
+			entity.ActionEditPost:             true,
+			entity.ActionDeletePost:           true,
+			entity.ActionRestorePost:          true,
+			entity.ActionPurgePosts:           true,
+			entity.ActionListUnpublishedPosts: true,
+			entity.ActionListDeletedPosts:     true,
+		}},
+	}
+
+	// The listing action gets the post standing for a listing of the posts
+	// of the author.
+	actions := []string{
+		entity.ActionViewPost, entity.ActionEditPost, entity.ActionDeletePost,
+		entity.ActionRestorePost, entity.ActionPurgePosts, entity.ActionListUnpublishedPosts,
+		entity.ActionListDeletedPosts,
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, action := range actions {
+				target := post
+				switch action {
+				case entity.ActionRestorePost, entity.ActionPurgePosts, entity.ActionListDeletedPosts:
+					target = nil
+				}
+
+				err := usecase.RolePolicy{}.Authorize(context.Background(), tc.principal, action, target)
+				if tc.allowed[action] {
+					require.NoError(t, err, action)
+				} else {
+					require.ErrorIs(t, err, entity.ErrForbidden, action)
+				}
+			}
+		})
+	}
+
+	// A post without an author is nobody's to edit.
+	err := usecase.RolePolicy{}.Authorize(context.Background(), noUser, entity.ActionEditPost, &entity.Post{})
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	// Authors list only their own unpublished posts.
+	err = usecase.RolePolicy{}.Authorize(context.Background(), author, entity.ActionListUnpublishedPosts, &entity.Post{})
+	require.ErrorIs(t, err, entity.ErrForbidden)
+}
+
+func TestRolePolicyComments(t *testing.T) {
+	t.Parallel()
+
+	var (
+		comment = &entity.Comment{Id: postId, UserId: authorId, APIKeyId: keyId}
+		edit    = entity.ActionEditComment
+		remove  = entity.ActionDeleteComment
+	)
+
+	tests := []struct {
+		name      string
+		principal *entity.Principal
+		allowed   map[string]bool
+	}{
+		{"anonymous", nil, nil},
+		{"author", &entity.Principal{UserId: authorId}, map[string]bool{edit: true, remove: true}},
+		{"other user", &entity.Principal{UserId: otherId}, nil},
+		{"moderator", &entity.Principal{UserId: otherId, Roles: []string{entity.RoleModerator}}, map[string]bool{edit: true}},
+		{"admin", &entity.Principal{UserId: otherId, Roles: []string{entity.RoleAdmin}}, map[string]bool{edit: true, remove: true}},
+		{"principal without user", &entity.Principal{}, nil},
+		{
+			"key that wrote the comment", &entity.Principal{APIKeyId: keyId, Scopes: []string{entity.ScopePostsWrite}},
+			map[string]bool{edit: true, remove: true},
+		},
+		{"read-only key that wrote the comment", &entity.Principal{APIKeyId: keyId, Scopes: []string{entity.ScopePostsRead}}, nil},
+		{"other key", &entity.Principal{APIKeyId: otherId, Scopes: []string{entity.ScopePostsWrite}}, nil},
+		{
+			"admin key", &entity.Principal{APIKeyId: otherId, Scopes: []string{entity.ScopeAdmin}},
+			map[string]bool{edit: true, remove: true},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, action := range []string{edit, remove} {
+				err := usecase.RolePolicy{}.AuthorizeComment(context.Background(), tc.principal, action, comment)
+				if tc.allowed[action] {
+					require.NoError(t, err, action)
+				} else {
+					require.ErrorIs(t, err, entity.ErrForbidden, action)
+				}
+			}
+		})
+	}
+}
+
+func TestPostAuthorization(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockPostRepo(mockCtl)
+	post := usecase.New(repo, cursor.New("secret"), noTx{})
+
+	as := func(p *entity.Principal) context.Context {
+		return entity.ContextWithPrincipal(context.Background(), p)
+	}
+
+	var (
+		other     = as(&entity.Principal{UserId: otherId})
+		moderator = as(&entity.Principal{UserId: otherId, Roles: []string{entity.RoleModerator}})
+		admin     = as(&entity.Principal{UserId: otherId, Roles: []string{entity.RoleAdmin}})
+	)
+
+	current := &entity.Post{Id: postId, UserId: authorId, Status: entity.StatusDraft, Version: 1}
+	repo.EXPECT().Get(gomock.Any(), postId).Return(current, nil).AnyTimes()
+
+	edit := func() *entity.Post {
+		return &entity.Post{
+			Id:       postId,
+			UserId:   otherId,
+			Content:  "Content",
+			Title:    "Post title",
+			Category: "Nature",
+			Version:  1,
+		}
+	}
+
+	_, err := post.UpdatePost(other, edit())
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	err = post.DeletePost(moderator, postId, 1)
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	_, err = post.ArchivePost(other, postId)
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	_, err = post.RestorePost(moderator, postId)
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	_, err = post.PurgeDeletedPosts(context.Background(), time.Hour)
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	// A moderator's edit keeps the post's author.
+	repo.EXPECT().Update(moderator, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *entity.Post) (*entity.Post, error) {
+			require.Equal(t, authorId, req.UserId)
+
+			return req, nil
+		})
+
+	_, err = post.UpdatePost(moderator, edit())
+	require.NoError(t, err)
+
+	repo.EXPECT().Purge(admin, time.Hour).Return(int64(2), nil)
+
+	purged, err := post.PurgeDeletedPosts(admin, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+}
+
+func TestUpdatePostWithAPIKey(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockPostRepo(mockCtl)
+	post := usecase.New(repo, cursor.New("secret"), noTx{})
+
+	var (
+		writer = entity.ContextWithPrincipal(context.Background(),
+			&entity.Principal{APIKeyId: keyId, Scopes: []string{entity.ScopePostsWrite}})
+		other = entity.ContextWithPrincipal(context.Background(),
+			&entity.Principal{APIKeyId: otherId, Scopes: []string{entity.ScopePostsWrite}})
+	)
+
+	current := &entity.Post{Id: postId, UserId: authorId, APIKeyId: keyId, Status: entity.StatusDraft, Version: 1}
+	repo.EXPECT().Get(gomock.Any(), postId).Return(current, nil).AnyTimes()
+
+	// Keys act as no user, so the request names none.
+	edit := func() *entity.Post {
+		return &entity.Post{Id: postId, Content: "Content", Title: "Post title", Category: "Nature", Version: 1}
+	}
+
+	_, err := post.UpdatePost(other, edit())
+	require.ErrorIs(t, err, entity.ErrForbidden)
+
+	repo.EXPECT().Update(writer, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *entity.Post) (*entity.Post, error) {
+			require.Equal(t, authorId, req.UserId)
+			require.Equal(t, keyId, req.APIKeyId)
+
+			return req, nil
+		})
+
+	_, err = post.UpdatePost(writer, edit())
+	require.NoError(t, err)
+}
+
+func TestPostVisibility(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockPostRepo(mockCtl)
+	post := usecase.New(repo, cursor.New("secret"), noTx{})
+
+	var (
+		anonymous = context.Background()
+		author    = entity.ContextWithPrincipal(anonymous, &entity.Principal{UserId: authorId})
+		other     = entity.ContextWithPrincipal(anonymous, &entity.Principal{UserId: otherId})
+		moderator = entity.ContextWithPrincipal(anonymous,
+			&entity.Principal{UserId: otherId, Roles: []string{entity.RoleModerator}})
+		admin = entity.ContextWithPrincipal(anonymous, &entity.Principal{UserId: otherId, Roles: []string{entity.RoleAdmin}})
+	)
+
+	for _, status := range []string{entity.StatusDraft, entity.StatusScheduled, entity.StatusArchived} {
+		id := status + "-id"
+		repo.EXPECT().Get(gomock.Any(), id).Return(&entity.Post{Id: id, UserId: authorId, Status: status, Version: 2}, nil).AnyTimes()
+
+		for _, ctx := range []context.Context{anonymous, other} {
+			_, err := post.ViewPost(ctx, id, "")
+			require.ErrorIs(t, err, entity.ErrNotFound, status)
+			require.NotErrorIs(t, err, entity.ErrForbidden, "hidden posts look missing")
+
+			_, err = post.ListRevisions(ctx, &entity.RevisionFilter{PostId: id})
+			require.ErrorIs(t, err, entity.ErrNotFound, status)
+
+			_, err = post.GetRevision(ctx, id, 2)
+			require.ErrorIs(t, err, entity.ErrNotFound, status)
+
+			_, err = post.DiffRevisions(ctx, id, 1, 2)
+			require.ErrorIs(t, err, entity.ErrNotFound, status)
+		}
+
+		for _, ctx := range []context.Context{author, moderator} {
+			_, err := post.ViewPost(ctx, id, "")
+			require.NoError(t, err, status)
+
+			revision, err := post.GetRevision(ctx, id, 2)
+			require.NoError(t, err, status)
+			require.Equal(t, int64(2), revision.Version)
+		}
+	}
+
+	drafts := func(userId string) *entity.GetListFilter {
+		return &entity.GetListFilter{UserId: userId, Statuses: []string{entity.StatusDraft}}
+	}
+
+	deleted := func(userId string) *entity.GetListFilter {
+		return &entity.GetListFilter{UserId: userId, Statuses: []string{entity.StatusPublished}, IncludeDeleted: true}
+	}
+
+	repo.EXPECT().List(gomock.Any(), gomock.Any()).Return(&entity.Posts{}, nil).Times(4)
+
+	_, err := post.ListPosts(anonymous, &entity.GetListFilter{Statuses: []string{entity.StatusPublished}})
+	require.NoError(t, err, "published posts are public")
+
+	_, err = post.ListPosts(author, drafts(authorId))
+	require.NoError(t, err, "authors list their own drafts")
+
+	_, err = post.ListPosts(moderator, drafts(""))
+	require.NoError(t, err)
+
+	_, err = post.ListPosts(admin, deleted(""))
+	require.NoError(t, err, "admins list deleted posts")
+
+	for name, tc := range map[string]struct {
+		ctx context.Context
+		req *entity.GetListFilter
+	}{
+		"anonymous":             {anonymous, drafts(authorId)},
+		"drafts of another":     {other, drafts(authorId)},
+		"every author's drafts": {author, drafts("")},
+		"every status":          {author, &entity.GetListFilter{UserId: otherId}},
+		"own deleted posts":     {author, deleted(authorId)},
+		"deleted posts":         {moderator, deleted("")},
+	} {
+		_, err = post.ListPosts(tc.ctx, tc.req)
+		require.ErrorIs(t, err, entity.ErrForbidden, name)
+
+		_, err = post.ListPostsByCursor(tc.ctx, tc.req)
+		require.ErrorIs(t, err, entity.ErrForbidden, name)
+
+		_, err = post.SearchPosts(tc.ctx, &entity.SearchFilter{Query: "q", GetListFilter: *tc.req})
+		require.ErrorIs(t, err, entity.ErrForbidden, name)
+	}
+}
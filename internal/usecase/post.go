@@ -4,20 +4,52 @@ import (
 	"context"
 	"fmt"
 	"fourth-exam/post-service-clean-arch/internal/entity"
+	"strings"
+	"time"
 )
 
 // PostUseCase -.
 type PostUseCase struct {
-	repo PostRepo
+	repo    PostRepo
+	cursors Cursor
+	tx      TxManager
+	authz   Authorizer
+	views   *viewBuffer
 }
 
 // New -.
-func New(p PostRepo) *PostUseCase {
-	return &PostUseCase{repo: p}
+func New(p PostRepo, c Cursor, tx TxManager, opts ...Option) *PostUseCase {
+	uc := &PostUseCase{repo: p, cursors: c, tx: tx, authz: RolePolicy{}, views: newViewBuffer()}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	return uc
+}
+
+// pageCursor is the payload of the opaque cursors handed out by ListPostsByCursor.
+type pageCursor struct {
+	Keyset   entity.Keyset `json:"k"`
+	Backward bool          `json:"b,omitempty"`
 }
 
 // Create Post
 func (p *PostUseCase) CreatePost(ctx context.Context, req *entity.Post) (*entity.Post, error) {
+	req.Tags = entity.NormalizeTags(req.Tags)
+	if req.Status == "" {
+		req.Status = entity.StatusDraft
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Create - req.Validate: %w", err)
+	}
+
+	if req.Status == entity.StatusPublished {
+		req.PublishedAt = time.Now().Format(time.RFC3339Nano)
+	}
+
 	post, err := p.repo.Create(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("PostUseCase - Create - p.repo: %w", err)
@@ -38,6 +70,24 @@ func (p *PostUseCase) GetPost(ctx context.Context, id string) (*entity.Post, err
 
 // Update Post
 func (p *PostUseCase) UpdatePost(ctx context.Context, req *entity.Post) (*entity.Post, error) {
+	if req.Version < 1 {
+		return nil, fmt.Errorf("PostUseCase - Update: %w", versionRequired())
+	}
+
+	req.Tags = entity.NormalizeTags(req.Tags)
+
+	if err := req.ValidateUpdate(); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Update - req.ValidateUpdate: %w", err)
+	}
+
+	current, err := p.authorizePost(ctx, entity.ActionEditPost, req.Id)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Update - p.authorizePost: %w", err)
+	}
+
+	// Editing doesn't change who wrote the post, whoever does it.
+	req.UserId, req.APIKeyId = current.UserId, current.APIKeyId
+
 	post, err := p.repo.Update(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("PostUseCase - Update - p.repo: %w", err)
@@ -46,9 +96,44 @@ func (p *PostUseCase) UpdatePost(ctx context.Context, req *entity.Post) (*entity
 	return post, nil
 }
 
+// Patch Post updates only the fields set in patch
+func (p *PostUseCase) PatchPost(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error) {
+	if patch.Version < 1 {
+		return nil, fmt.Errorf("PostUseCase - Patch: %w", versionRequired())
+	}
+
+	if patch.Tags != nil {
+		tags := entity.NormalizeTags(*patch.Tags)
+		patch.Tags = &tags
+	}
+
+	if err := patch.Validate(); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Patch - patch.Validate: %w", err)
+	}
+
+	if _, err := p.authorizePost(ctx, entity.ActionEditPost, id); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Patch - p.authorizePost: %w", err)
+	}
+
+	post, err := p.repo.Patch(ctx, id, patch)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Patch - p.repo: %w", err)
+	}
+
+	return post, nil
+}
+
 // Delete Post
-func (p *PostUseCase) DeletePost(ctx context.Context, id string) error {
-	err := p.repo.Delete(ctx, id)
+func (p *PostUseCase) DeletePost(ctx context.Context, id string, version int64) error {
+	if version < 1 {
+		return fmt.Errorf("PostUseCase - Delete: %w", versionRequired())
+	}
+
+	if _, err := p.authorizePost(ctx, entity.ActionDeletePost, id); err != nil {
+		return fmt.Errorf("PostUseCase - Delete - p.authorizePost: %w", err)
+	}
+
+	err := p.repo.Delete(ctx, id, version)
 	if err != nil {
 		return fmt.Errorf("PostUseCase - Delete - p.repo: %w", err)
 	}
@@ -56,8 +141,129 @@ func (p *PostUseCase) DeletePost(ctx context.Context, id string) error {
 	return nil
 }
 
+// Restore soft-deleted Post
+func (p *PostUseCase) RestorePost(ctx context.Context, id string) (*entity.Post, error) {
+	if err := p.authorize(ctx, entity.ActionRestorePost, nil); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Restore - p.authorize: %w", err)
+	}
+
+	var post *entity.Post
+
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.repo.Restore(ctx, id); err != nil {
+			return fmt.Errorf("p.repo: %w", err)
+		}
+
+		var err error
+		if post, err = p.repo.Get(ctx, id); err != nil {
+			return fmt.Errorf("p.repo.Get: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Restore - %w", err)
+	}
+
+	return post, nil
+}
+
+// PurgeDeletedPosts permanently removes posts soft-deleted more than retention ago
+func (p *PostUseCase) PurgeDeletedPosts(ctx context.Context, retention time.Duration) (int64, error) {
+	if err := p.authorize(ctx, entity.ActionPurgePosts, nil); err != nil {
+		return 0, fmt.Errorf("PostUseCase - PurgeDeletedPosts - p.authorize: %w", err)
+	}
+
+	purged, err := p.repo.Purge(ctx, retention)
+	if err != nil {
+		return 0, fmt.Errorf("PostUseCase - PurgeDeletedPosts - p.repo: %w", err)
+	}
+
+	return purged, nil
+}
+
+// Publish Post makes the post live now, or schedules it when at is in the future
+func (p *PostUseCase) PublishPost(ctx context.Context, id string, at time.Time) (*entity.Post, error) {
+	now := time.Now()
+
+	change := &entity.StatusChange{To: entity.StatusPublished, PublishedAt: &now}
+	if at.After(now) {
+		change.To = entity.StatusScheduled
+		change.PublishedAt = &at
+	}
+
+	post, err := p.transition(ctx, id, change)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Publish - p.transition: %w", err)
+	}
+
+	return post, nil
+}
+
+// Unpublish Post takes the post back to draft
+func (p *PostUseCase) UnpublishPost(ctx context.Context, id string) (*entity.Post, error) {
+	post, err := p.transition(ctx, id, &entity.StatusChange{To: entity.StatusDraft, ClearPublishedAt: true})
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Unpublish - p.transition: %w", err)
+	}
+
+	return post, nil
+}
+
+// Archive Post takes the post out of listings, keeping when it was published
+func (p *PostUseCase) ArchivePost(ctx context.Context, id string) (*entity.Post, error) {
+	post, err := p.transition(ctx, id, &entity.StatusChange{To: entity.StatusArchived})
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Archive - p.transition: %w", err)
+	}
+
+	return post, nil
+}
+
+// PublishScheduledPosts publishes the scheduled posts whose time has come
+func (p *PostUseCase) PublishScheduledPosts(ctx context.Context) (int64, error) {
+	published, err := p.repo.PublishDue(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("PostUseCase - PublishScheduledPosts - p.repo: %w", err)
+	}
+
+	return published, nil
+}
+
+// transition applies change to the post if its current status allows it.
+func (p *PostUseCase) transition(ctx context.Context, id string, change *entity.StatusChange) (*entity.Post, error) {
+	var post *entity.Post
+
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := p.authorizePost(ctx, entity.ActionEditPost, id)
+		if err != nil {
+			return fmt.Errorf("p.authorizePost: %w", err)
+		}
+
+		if !entity.CanTransition(current.Status, change.To) {
+			return fmt.Errorf("%s to %s: %w", current.Status, change.To, entity.ErrInvalidTransition)
+		}
+		change.From = current.Status
+
+		if post, err = p.repo.Transition(ctx, id, change); err != nil {
+			return fmt.Errorf("p.repo.Transition: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
 // List posts
 func (p *PostUseCase) ListPosts(ctx context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
+	if err := p.authorizeList(ctx, req); err != nil {
+		return nil, fmt.Errorf("PostUseCase - List - p.authorizeList: %w", err)
+	}
+
 	posts, err := p.repo.List(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("PostUseCase - List - p.repo: %w", err)
@@ -65,3 +271,153 @@ func (p *PostUseCase) ListPosts(ctx context.Context, req *entity.GetListFilter)
 
 	return posts, nil
 }
+
+// ListPostsByCursor lists posts newest first using keyset pagination
+func (p *PostUseCase) ListPostsByCursor(ctx context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
+	if err := p.authorizeList(ctx, req); err != nil {
+		return nil, fmt.Errorf("PostUseCase - ListByCursor - p.authorizeList: %w", err)
+	}
+
+	if req.Cursor != "" {
+		var c pageCursor
+		if err := p.cursors.Decode(req.Cursor, &c); err != nil {
+			return nil, fmt.Errorf("PostUseCase - ListByCursor - p.cursors.Decode: %w: %w", entity.ErrValidation, err)
+		}
+
+		req.Keyset, req.Backward = &c.Keyset, c.Backward
+	}
+
+	posts, err := p.repo.ListByCursor(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - ListByCursor - p.repo: %w", err)
+	}
+
+	if len(posts.Items) == 0 {
+		return posts, nil
+	}
+
+	// Going forward there is a next page if the repo found more rows and a
+	// previous one if we started from a cursor; going backward it is the
+	// other way round.
+	hasNext, hasPrev := posts.HasMore, req.Keyset != nil
+	if req.Backward {
+		hasNext, hasPrev = req.Keyset != nil, posts.HasMore
+	}
+
+	if hasNext {
+		posts.NextCursor, err = p.cursorAt(posts.Items[len(posts.Items)-1], false)
+		if err != nil {
+			return nil, fmt.Errorf("PostUseCase - ListByCursor - next: %w", err)
+		}
+	}
+	if hasPrev {
+		posts.PrevCursor, err = p.cursorAt(posts.Items[0], true)
+		if err != nil {
+			return nil, fmt.Errorf("PostUseCase - ListByCursor - prev: %w", err)
+		}
+	}
+	posts.HasMore = hasNext
+
+	return posts, nil
+}
+
+func (p *PostUseCase) cursorAt(post *entity.Post, backward bool) (string, error) {
+	keyset, err := post.Keyset()
+	if err != nil {
+		return "", fmt.Errorf("post.Keyset: %w", err)
+	}
+
+	return p.cursors.Encode(pageCursor{Keyset: keyset, Backward: backward})
+}
+
+// SearchPosts runs a full-text search over post titles and content
+func (p *PostUseCase) SearchPosts(ctx context.Context, req *entity.SearchFilter) (*entity.SearchResults, error) {
+	v := &entity.ValidationError{}
+	if strings.TrimSpace(req.Query) == "" {
+		v.Add("q", entity.CodeRequired)
+	}
+	if err := v.Err(); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Search: %w", err)
+	}
+
+	if err := p.authorizeList(ctx, &req.GetListFilter); err != nil {
+		return nil, fmt.Errorf("PostUseCase - Search - p.authorizeList: %w", err)
+	}
+
+	results, err := p.repo.Search(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Search - p.repo: %w", err)
+	}
+
+	return results, nil
+}
+
+// React likes or dislikes a post on behalf of a user
+func (p *PostUseCase) React(ctx context.Context, req *entity.Reaction) (*entity.Post, error) {
+	if req.Reaction != entity.ReactionLike && req.Reaction != entity.ReactionDislike {
+		return nil, fmt.Errorf("PostUseCase - React: unknown reaction %q: %w", req.Reaction, entity.ErrValidation)
+	}
+
+	var post *entity.Post
+
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.repo.React(ctx, req); err != nil {
+			return fmt.Errorf("p.repo: %w", err)
+		}
+
+		var err error
+		if post, err = p.repo.Get(ctx, req.PostId); err != nil {
+			return fmt.Errorf("p.repo.Get: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - React - %w", err)
+	}
+
+	return post, nil
+}
+
+// Unreact removes a user's reaction from a post
+func (p *PostUseCase) Unreact(ctx context.Context, req *entity.Reaction) (*entity.Post, error) {
+	var post *entity.Post
+
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.repo.Unreact(ctx, req.PostId, req.UserId); err != nil {
+			return fmt.Errorf("p.repo: %w", err)
+		}
+
+		var err error
+		if post, err = p.repo.Get(ctx, req.PostId); err != nil {
+			return fmt.Errorf("p.repo.Get: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Unreact - %w", err)
+	}
+
+	return post, nil
+}
+
+// List Tags with the number of posts using them, most used first
+func (p *PostUseCase) ListTags(ctx context.Context, req *entity.TagFilter) (*entity.Tags, error) {
+	req.Prefix = entity.NormalizeTag(req.Prefix)
+
+	tags, err := p.repo.ListTags(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - ListTags - p.repo: %w", err)
+	}
+
+	return tags, nil
+}
+
+// versionRequired is returned when a write doesn't say which version of the post it is based on.
+func versionRequired() error {
+	v := &entity.ValidationError{}
+	v.Add("version", entity.CodeRequired)
+
+	return v
+}
@@ -3,9 +3,12 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"fourth-exam/post-service-clean-arch/internal/entity"
 	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/cursor"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -13,10 +16,9 @@ import (
 
 var (
 	errInternalServerErr = errors.New("internal server error")
-	errBadRequest = errors.New("bad request")
+	errBadRequest        = errors.New("bad request")
 )
 
-
 type test struct {
 	name string
 	mock func()
@@ -24,6 +26,20 @@ type test struct {
 	err  error
 }
 
+// noTx runs functions without a transaction, as the repo mocks need none.
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// allowAll lets anyone do anything, for tests that aren't about authorization.
+type allowAll struct{}
+
+func (allowAll) Authorize(context.Context, *entity.Principal, string, *entity.Post) error {
+	return nil
+}
+
 func post(t *testing.T) (*usecase.PostUseCase, *MockPostRepo) {
 	t.Helper()
 
@@ -32,7 +48,7 @@ func post(t *testing.T) (*usecase.PostUseCase, *MockPostRepo) {
 
 	repo := NewMockPostRepo(mockCtl)
 
-	post := usecase.New(repo)
+	post := usecase.New(repo, cursor.New("secret"), noTx{}, usecase.Policy(allowAll{}))
 
 	return post, repo
 }
@@ -43,13 +59,10 @@ func TestPost(t *testing.T) {
 	post, repo := post(t)
 
 	body := entity.Post{
-		UserId: "",
-		Content: "Content",
-		Title: "Post title",
-		Likes: 0,
-		Dislikes: 0,
-		Views: 10,
-		Category: "Post category",
+		UserId:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+		Content:  "Content",
+		Title:    "Post title",
+		Category: "Nature",
 	}
 
 	tests := []test{
@@ -106,3 +119,324 @@ func TestPost(t *testing.T) {
 		require.ErrorIs(t, err, createTest.err)
 	})
 }
+
+func TestReact(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	liked := &entity.Post{Id: "post-id", Likes: 1}
+
+	t.Run("unknown reaction", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := post.React(context.Background(), &entity.Reaction{PostId: "post-id", UserId: "user-id", Reaction: "love"})
+
+		require.Nil(t, res)
+		require.ErrorIs(t, err, entity.ErrValidation)
+	})
+
+	t.Run("like returns refreshed post", func(t *testing.T) {
+		t.Parallel()
+
+		req := &entity.Reaction{PostId: "post-id", UserId: "user-id", Reaction: entity.ReactionLike}
+		gomock.InOrder(
+			repo.EXPECT().React(context.Background(), req).Return(nil),
+			repo.EXPECT().Get(context.Background(), "post-id").Return(liked, nil),
+		)
+
+		res, err := post.React(context.Background(), req)
+
+		require.NoError(t, err)
+		require.Equal(t, liked, res)
+	})
+
+	t.Run("unreact with error", func(t *testing.T) {
+		t.Parallel()
+
+		repo.EXPECT().Unreact(context.Background(), "other-post", "user-id").Return(errInternalServerErr)
+
+		res, err := post.Unreact(context.Background(), &entity.Reaction{PostId: "other-post", UserId: "user-id"})
+
+		require.Nil(t, res)
+		require.ErrorIs(t, err, errInternalServerErr)
+	})
+}
+
+func TestRestorePost(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	restored := &entity.Post{Id: "post-id"}
+	gomock.InOrder(
+		repo.EXPECT().Restore(context.Background(), "post-id").Return(nil),
+		repo.EXPECT().Get(context.Background(), "post-id").Return(restored, nil),
+	)
+
+	res, err := post.RestorePost(context.Background(), "post-id")
+
+	require.NoError(t, err)
+	require.Equal(t, restored, res)
+}
+
+func TestCreatePostValidation(t *testing.T) {
+	t.Parallel()
+
+	post, _ := post(t)
+
+	res, err := post.CreatePost(context.Background(), &entity.Post{
+		UserId:   "not-a-uuid",
+		Content:  "Content",
+		Category: "Unknown",
+		Views:    10,
+	})
+
+	var validationErr *entity.ValidationError
+
+	require.Nil(t, res)
+	require.ErrorIs(t, err, entity.ErrValidation)
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, []entity.FieldError{
+		{Field: "user_id", Code: entity.CodeInvalidUUID},
+		{Field: "title", Code: entity.CodeRequired},
+		{Field: "category", Code: entity.CodeInvalidChoice},
+		{Field: "views", Code: entity.CodeReadOnly},
+	}, validationErr.Errors)
+}
+
+func TestListPostsByCursor(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	last := &entity.Post{Id: "b0b69f3b-2021-4d91-8e13-c243d9eb5292", CreatedAt: "2024-03-01 10:00:00.123456 +0000 UTC"}
+	page := &entity.Posts{Count: 1, Items: []*entity.Post{last}, HasMore: true}
+
+	repo.EXPECT().ListByCursor(context.Background(), &entity.GetListFilter{Limit: 1}).Return(page, nil)
+
+	first, err := post.ListPostsByCursor(context.Background(), &entity.GetListFilter{Limit: 1})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NextCursor)
+	require.Empty(t, first.PrevCursor)
+
+	repo.EXPECT().ListByCursor(context.Background(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
+			require.False(t, req.Backward)
+			require.Equal(t, last.Id, req.Keyset.Id)
+			require.Equal(t, last.CreatedAt, req.Keyset.CreatedAt.String())
+
+			return &entity.Posts{}, nil
+		})
+
+	_, err = post.ListPostsByCursor(context.Background(), &entity.GetListFilter{Limit: 1, Cursor: first.NextCursor})
+	require.NoError(t, err)
+
+	_, err = post.ListPostsByCursor(context.Background(), &entity.GetListFilter{Limit: 1, Cursor: first.NextCursor + "x"})
+	require.ErrorIs(t, err, entity.ErrValidation)
+}
+
+func TestSearchPosts(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	_, err := post.SearchPosts(context.Background(), &entity.SearchFilter{Query: "  "})
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	req := &entity.SearchFilter{Query: "clean architecture", GetListFilter: entity.GetListFilter{Page: 1, Limit: 10}}
+	results := &entity.SearchResults{Count: 1, Items: []*entity.SearchResult{{Post: &entity.Post{Id: "post-id"}, Rank: 0.5}}}
+	repo.EXPECT().Search(context.Background(), req).Return(results, nil)
+
+	res, err := post.SearchPosts(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, results, res)
+}
+
+func TestVersionedWrites(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	body := &entity.Post{
+		Id:       "b0b69f3b-2021-4d91-8e13-c243d9eb5292",
+		UserId:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+		Content:  "Content",
+		Title:    "Post title",
+		Category: "Nature",
+	}
+
+	_, err := post.UpdatePost(context.Background(), body)
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	err = post.DeletePost(context.Background(), body.Id, 0)
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	body.Version = 2
+	repo.EXPECT().Get(context.Background(), body.Id).Return(&entity.Post{Id: body.Id, UserId: body.UserId}, nil).Times(2)
+	repo.EXPECT().Update(context.Background(), body).Return(nil, entity.ErrVersionConflict)
+
+	_, err = post.UpdatePost(context.Background(), body)
+	require.ErrorIs(t, err, entity.ErrVersionConflict)
+	require.ErrorIs(t, err, entity.ErrConflict)
+
+	repo.EXPECT().Delete(context.Background(), body.Id, int64(2)).Return(nil)
+
+	require.NoError(t, post.DeletePost(context.Background(), body.Id, 2))
+}
+
+func TestPatchPost(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	title, empty := "New title", ""
+
+	_, err := post.PatchPost(context.Background(), "post-id", &entity.PostPatch{Title: &title})
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	_, err = post.PatchPost(context.Background(), "post-id", &entity.PostPatch{Content: &empty, Version: 1})
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	patch := &entity.PostPatch{Title: &title, Version: 1}
+	patched := &entity.Post{Id: "post-id", Title: title, Version: 2}
+	repo.EXPECT().Get(context.Background(), "post-id").Return(&entity.Post{Id: "post-id", Version: 1}, nil)
+	repo.EXPECT().Patch(context.Background(), "post-id", patch).Return(patched, nil)
+
+	res, err := post.PatchPost(context.Background(), "post-id", patch)
+	require.NoError(t, err)
+	require.Equal(t, patched, res)
+}
+
+func TestCreatePostTags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normalized", func(t *testing.T) {
+		t.Parallel()
+
+		post, repo := post(t)
+
+		req := &entity.Post{
+			UserId:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+			Content:  "Content",
+			Title:    "Post title",
+			Category: "Nature",
+			Tags:     []string{" Go Lang ", "go-lang", "#Databases!", "  "},
+		}
+		repo.EXPECT().Create(context.Background(), req).Return(req, nil)
+
+		res, err := post.CreatePost(context.Background(), req)
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"databases", "go-lang"}, res.Tags)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		t.Parallel()
+
+		post, _ := post(t)
+
+		tags := make([]string, entity.MaxTags+1)
+		for i := range tags {
+			tags[i] = fmt.Sprintf("tag-%d", i)
+		}
+
+		res, err := post.CreatePost(context.Background(), &entity.Post{
+			UserId:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+			Content:  "Content",
+			Title:    "Post title",
+			Category: "Nature",
+			Tags:     tags,
+		})
+
+		var validationErr *entity.ValidationError
+
+		require.Nil(t, res)
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []entity.FieldError{{Field: "tags", Code: entity.CodeTooMany}}, validationErr.Errors)
+	})
+}
+
+func TestPostLifecycle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("publish now", func(t *testing.T) {
+		t.Parallel()
+
+		post, repo := post(t)
+
+		published := &entity.Post{Id: "post-id", Status: entity.StatusPublished}
+		gomock.InOrder(
+			repo.EXPECT().Get(context.Background(), "post-id").Return(&entity.Post{Id: "post-id", Status: entity.StatusDraft}, nil),
+			repo.EXPECT().Transition(context.Background(), "post-id", gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, change *entity.StatusChange) (*entity.Post, error) {
+					require.Equal(t, entity.StatusDraft, change.From)
+					require.Equal(t, entity.StatusPublished, change.To)
+					require.NotNil(t, change.PublishedAt)
+
+					return published, nil
+				}),
+		)
+
+		res, err := post.PublishPost(context.Background(), "post-id", time.Time{})
+
+		require.NoError(t, err)
+		require.Equal(t, published, res)
+	})
+
+	t.Run("schedule", func(t *testing.T) {
+		t.Parallel()
+
+		post, repo := post(t)
+
+		at := time.Now().Add(time.Hour)
+		gomock.InOrder(
+			repo.EXPECT().Get(context.Background(), "post-id").Return(&entity.Post{Id: "post-id", Status: entity.StatusDraft}, nil),
+			repo.EXPECT().Transition(context.Background(), "post-id", &entity.StatusChange{
+				From:        entity.StatusDraft,
+				To:          entity.StatusScheduled,
+				PublishedAt: &at,
+			}).Return(&entity.Post{Id: "post-id", Status: entity.StatusScheduled}, nil),
+		)
+
+		_, err := post.PublishPost(context.Background(), "post-id", at)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		t.Parallel()
+
+		post, repo := post(t)
+
+		repo.EXPECT().Get(context.Background(), "post-id").Return(&entity.Post{Id: "post-id", Status: entity.StatusArchived}, nil)
+
+		res, err := post.ArchivePost(context.Background(), "post-id")
+
+		require.Nil(t, res)
+		require.ErrorIs(t, err, entity.ErrInvalidTransition)
+		require.ErrorIs(t, err, entity.ErrConflict)
+	})
+
+	t.Run("update cannot change status", func(t *testing.T) {
+		t.Parallel()
+
+		post, _ := post(t)
+
+		res, err := post.UpdatePost(context.Background(), &entity.Post{
+			Id:       "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+			UserId:   "d0b69f3b-2021-4d91-8e13-c243d9eb5292",
+			Content:  "Content",
+			Title:    "Post title",
+			Category: "Nature",
+			Status:   entity.StatusPublished,
+			Version:  1,
+		})
+
+		var validationErr *entity.ValidationError
+
+		require.Nil(t, res)
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []entity.FieldError{{Field: "status", Code: entity.CodeReadOnly}}, validationErr.Errors)
+	})
+}
@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryAPIKeyRepo is an APIKeyRepo keeping keys in memory, next to the
+// posts of a MemoryPostRepo. Keys are gone when the service stops.
+type MemoryAPIKeyRepo struct {
+	*memoryStore
+}
+
+// memoryAPIKey is an api_keys row.
+type memoryAPIKey struct {
+	entity.APIKey
+
+	createdAt  time.Time
+	lastUsedAt *time.Time
+	revokedAt  *time.Time
+}
+
+// NewMemoryAPIKey -.
+func NewMemoryAPIKey(posts *MemoryPostRepo) *MemoryAPIKeyRepo {
+	return &MemoryAPIKeyRepo{posts.memoryStore}
+}
+
+// Create API key -.
+func (a *MemoryAPIKeyRepo) Create(_ context.Context, req *entity.APIKey) (*entity.APIKey, error) {
+	if req.Id == "" {
+		req.Id = uuid.New().String()
+	}
+
+	if err := checkIds(req.Id); err != nil {
+		return nil, fmt.Errorf("MemoryAPIKeyRepo - Create: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, row := range a.apiKeys {
+		if row.Id == req.Id || row.Prefix == req.Prefix {
+			return nil, fmt.Errorf("MemoryAPIKeyRepo - Create: key %s exists: %w", req.Prefix, entity.ErrConflict)
+		}
+	}
+
+	row := &memoryAPIKey{APIKey: *req, createdAt: *timestamp(time.Now())}
+	row.Scopes = append([]string(nil), req.Scopes...)
+	row.Hash = append([]byte(nil), req.Hash...)
+	a.apiKeys[req.Id] = row
+
+	return row.entity(), nil
+}
+
+// GetByPrefix gets the key with prefix, revoked or not -.
+func (a *MemoryAPIKeyRepo) GetByPrefix(_ context.Context, prefix string) (*entity.APIKey, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, row := range a.apiKeys {
+		if row.Prefix == prefix {
+			return row.entity(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("MemoryAPIKeyRepo - GetByPrefix: %w", entity.ErrNotFound)
+}
+
+// List API keys, newest first -.
+func (a *MemoryAPIKeyRepo) List(_ context.Context) (*entity.APIKeys, error) {
+	a.mu.RLock()
+	rows := make([]*memoryAPIKey, 0, len(a.apiKeys))
+	for _, row := range a.apiKeys {
+		rows = append(rows, row)
+	}
+	a.mu.RUnlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].createdAt.Equal(rows[j].createdAt) {
+			return rows[i].createdAt.After(rows[j].createdAt)
+		}
+
+		return rows[i].Id < rows[j].Id
+	})
+
+	keys := entity.APIKeys{Count: int64(len(rows))}
+	for _, row := range rows {
+		keys.Items = append(keys.Items, row.entity())
+	}
+
+	return &keys, nil
+}
+
+// Revoke API key. Revoking a revoked key keeps the time it was first revoked -.
+func (a *MemoryAPIKeyRepo) Revoke(_ context.Context, id string) error {
+	if err := checkIds(id); err != nil {
+		return fmt.Errorf("MemoryAPIKeyRepo - Revoke: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	row, ok := a.apiKeys[id]
+	if !ok {
+		return fmt.Errorf("MemoryAPIKeyRepo - Revoke: %w", entity.ErrNotFound)
+	}
+
+	if row.revokedAt == nil {
+		row.revokedAt = timestamp(time.Now())
+	}
+
+	return nil
+}
+
+// TouchLastUsed sets the last use of the keys. A key keeps the latest of its
+// last uses -.
+func (a *MemoryAPIKeyRepo) TouchLastUsed(_ context.Context, used map[string]time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for id, at := range used {
+		row, ok := a.apiKeys[id]
+		if !ok {
+			continue
+		}
+
+		if row.lastUsedAt == nil || at.After(*row.lastUsedAt) {
+			row.lastUsedAt = timestamp(at)
+		}
+	}
+
+	return nil
+}
+
+// entity returns a copy of the key with its timestamps formatted like scanAPIKey does.
+func (m *memoryAPIKey) entity() *entity.APIKey {
+	key := m.APIKey
+	key.Scopes = append([]string(nil), m.Scopes...)
+	key.CreatedAt = m.createdAt.String()
+	key.LastUsedAt, key.RevokedAt = "", ""
+
+	if m.lastUsedAt != nil {
+		key.LastUsedAt = m.lastUsedAt.String()
+	}
+	if m.revokedAt != nil {
+		key.RevokedAt = m.revokedAt.String()
+	}
+
+	return &key
+}
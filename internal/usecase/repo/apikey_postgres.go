@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// _apiKeyColumns are the columns scanned by scanAPIKey.
+const _apiKeyColumns = `
+		id,
+		name,
+		prefix,
+		hash,
+		scopes,
+		rate_limit,
+		created_at,
+		last_used_at,
+		revoked_at
+		`
+
+// APIKeyRepo -.
+type APIKeyRepo struct {
+	*postgres.Postgres
+}
+
+// NewAPIKey -.
+func NewAPIKey(pg *postgres.Postgres) *APIKeyRepo {
+	return &APIKeyRepo{pg}
+}
+
+// Create API key -.
+func (a *APIKeyRepo) Create(ctx context.Context, req *entity.APIKey) (*entity.APIKey, error) {
+	if req.Id == "" {
+		req.Id = uuid.New().String()
+	}
+
+	q, args, err := a.Builder.Insert("api_keys").
+		Columns("id", "name", "prefix", "hash", "scopes", "rate_limit", "created_at").
+		Values(req.Id, req.Name, req.Prefix, req.Hash, req.Scopes, req.RateLimit, time.Now()).
+		Suffix("RETURNING " + _apiKeyColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - Create - a.Builder: %w", err)
+	}
+
+	key, err := scanAPIKey(a.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - Create row.Scan: %w", translateError(err))
+	}
+
+	return key, nil
+}
+
+// GetByPrefix gets the key with prefix, revoked or not -.
+func (a *APIKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*entity.APIKey, error) {
+	q, args, err := a.Builder.Select(_apiKeyColumns).From("api_keys").
+		Where(squirrel.Eq{"prefix": prefix}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - GetByPrefix - a.Builder: %w", err)
+	}
+
+	key, err := scanAPIKey(a.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - GetByPrefix row.Scan: %w", translateError(err))
+	}
+
+	return key, nil
+}
+
+// List API keys, newest first -.
+func (a *APIKeyRepo) List(ctx context.Context) (*entity.APIKeys, error) {
+	q, args, err := a.Builder.Select(_apiKeyColumns).From("api_keys").
+		OrderBy("created_at DESC", "id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - List - a.Builder: %w", err)
+	}
+
+	rows, err := a.Querier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - List - a.Querier.Query: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	keys := entity.APIKeys{}
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("APIKeyRepo - List row.Scan: %w", translateError(err))
+		}
+
+		keys.Items = append(keys.Items, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("APIKeyRepo - List rows.Err: %w", translateError(err))
+	}
+	keys.Count = int64(len(keys.Items))
+
+	return &keys, nil
+}
+
+// Revoke API key. Revoking a revoked key keeps the time it was first revoked -.
+func (a *APIKeyRepo) Revoke(ctx context.Context, id string) error {
+	q, args, err := a.Builder.Update("api_keys").
+		Set("revoked_at", squirrel.Expr("COALESCE(revoked_at, ?)", time.Now())).
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo - Revoke - a.Builder: %w", err)
+	}
+
+	tag, err := a.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo - Revoke - a.Querier.Exec: %w", translateError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("APIKeyRepo - Revoke: %w", entity.ErrNotFound)
+	}
+
+	return nil
+}
+
+// TouchLastUsed sets the last use of the keys, all in one statement. A key
+// keeps the latest of its last uses -.
+func (a *APIKeyRepo) TouchLastUsed(ctx context.Context, used map[string]time.Time) error {
+	ids := make([]string, 0, len(used))
+	ats := make([]time.Time, 0, len(used))
+	for id, at := range used {
+		ids = append(ids, id)
+		ats = append(ats, at)
+	}
+
+	q, args, err := a.Builder.Update("api_keys").
+		Set("last_used_at", squirrel.Expr("GREATEST(api_keys.last_used_at, u.at)")).
+		FromSelect(squirrel.Select().Column("unnest(?::uuid[]) AS id", ids).Column("unnest(?::timestamp[]) AS at", ats), "u").
+		Where("api_keys.id = u.id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo - TouchLastUsed - a.Builder: %w", err)
+	}
+
+	_, err = a.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("APIKeyRepo - TouchLastUsed - a.Querier.Exec: %w", translateError(err))
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (*entity.APIKey, error) {
+	var (
+		key        entity.APIKey
+		createdAt  time.Time
+		lastUsedAt sql.NullTime
+		revokedAt  sql.NullTime
+	)
+
+	if err := row.Scan(&key.Id, &key.Name, &key.Prefix, &key.Hash, &key.Scopes,
+		&key.RateLimit, &createdAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	key.CreatedAt = createdAt.String()
+	if lastUsedAt.Valid {
+		key.LastUsedAt = lastUsedAt.Time.String()
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = revokedAt.Time.String()
+	}
+
+	return &key, nil
+}
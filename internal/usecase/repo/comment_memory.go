@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryCommentRepo is a CommentRepo keeping comments in memory, next to the
+// posts of a MemoryPostRepo whose comments_count it maintains.
+type MemoryCommentRepo struct {
+	*memoryStore
+}
+
+// memoryComment is a comments row.
+type memoryComment struct {
+	entity.Comment
+
+	createdAt time.Time
+	updatedAt *time.Time
+}
+
+// NewMemoryComment -.
+func NewMemoryComment(posts *MemoryPostRepo) *MemoryCommentRepo {
+	return &MemoryCommentRepo{posts.memoryStore}
+}
+
+// Create Comment adds the comment and bumps the post's comments_count. The
+// post must exist and not be deleted -.
+func (c *MemoryCommentRepo) Create(_ context.Context, req *entity.Comment) (*entity.Comment, error) {
+	if req.Id == "" {
+		req.Id = uuid.New().String()
+	}
+
+	if err := checkIds(req.Id, req.PostId, req.UserId); err != nil {
+		return nil, fmt.Errorf("MemoryCommentRepo - CreateComment: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	post, err := c.live(req.PostId)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryCommentRepo - CreateComment: %w", err)
+	}
+
+	if req.ParentId != "" {
+		if parent, ok := c.comments[req.ParentId]; !ok || parent.PostId != req.PostId {
+			v := &entity.ValidationError{}
+			v.Add("parent_id", entity.CodeNotFound)
+
+			return nil, fmt.Errorf("MemoryCommentRepo - CreateComment: %w", v)
+		}
+	}
+
+	if _, ok := c.comments[req.Id]; ok {
+		return nil, fmt.Errorf("MemoryCommentRepo - CreateComment: comment %s exists: %w", req.Id, entity.ErrConflict)
+	}
+
+	row := &memoryComment{Comment: *req, createdAt: *timestamp(time.Now())}
+	c.comments[req.Id] = row
+	post.CommentsCount++
+
+	return row.entity(), nil
+}
+
+// Get Comment -.
+func (c *MemoryCommentRepo) Get(_ context.Context, postId, id string) (*entity.Comment, error) {
+	if err := checkIds(postId, id); err != nil {
+		return nil, fmt.Errorf("MemoryCommentRepo - GetComment: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	row, ok := c.comments[id]
+	if !ok || row.PostId != postId {
+		return nil, fmt.Errorf("MemoryCommentRepo - GetComment: %w", entity.ErrNotFound)
+	}
+
+	return row.entity(), nil
+}
+
+// Update Comment changes the content of the comment -.
+func (c *MemoryCommentRepo) Update(_ context.Context, req *entity.Comment) (*entity.Comment, error) {
+	if err := checkIds(req.PostId, req.Id); err != nil {
+		return nil, fmt.Errorf("MemoryCommentRepo - UpdateComment: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	row, ok := c.comments[req.Id]
+	if !ok || row.PostId != req.PostId {
+		return nil, fmt.Errorf("MemoryCommentRepo - UpdateComment: %w", entity.ErrNotFound)
+	}
+
+	row.Content = req.Content
+	row.updatedAt = timestamp(time.Now())
+
+	return row.entity(), nil
+}
+
+// Delete Comment removes the comment and its whole reply thread, taking them
+// off the post's comments_count -.
+func (c *MemoryCommentRepo) Delete(_ context.Context, postId, id string) error {
+	if err := checkIds(postId, id); err != nil {
+		return fmt.Errorf("MemoryCommentRepo - DeleteComment: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	post, err := c.live(postId)
+	if err != nil {
+		return fmt.Errorf("MemoryCommentRepo - DeleteComment: %w", err)
+	}
+
+	if row, ok := c.comments[id]; !ok || row.PostId != postId {
+		return fmt.Errorf("MemoryCommentRepo - DeleteComment: %w", entity.ErrNotFound)
+	}
+
+	thread := []string{id}
+	for i := 0; i < len(thread); i++ {
+		for replyId, reply := range c.comments {
+			if reply.ParentId == thread[i] {
+				thread = append(thread, replyId)
+			}
+		}
+	}
+
+	for _, commentId := range thread {
+		delete(c.comments, commentId)
+	}
+	post.CommentsCount -= int64(len(thread))
+
+	return nil
+}
+
+// List Comments of a post, oldest first -.
+func (c *MemoryCommentRepo) List(_ context.Context, req *entity.CommentFilter) (*entity.Comments, error) {
+	if err := checkIds(req.PostId); err != nil {
+		return nil, fmt.Errorf("MemoryCommentRepo - ListComments: %w", err)
+	}
+	if req.ParentId != "" {
+		if err := checkIds(req.ParentId); err != nil {
+			return nil, fmt.Errorf("MemoryCommentRepo - ListComments: %w", err)
+		}
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var rows []*memoryComment
+	for _, row := range c.comments {
+		if row.PostId == req.PostId && (req.ParentId == "" || row.ParentId == req.ParentId) {
+			rows = append(rows, row)
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].createdAt.Equal(rows[j].createdAt) {
+			return rows[i].createdAt.Before(rows[j].createdAt)
+		}
+
+		return rows[i].Id < rows[j].Id
+	})
+
+	comments := entity.Comments{Total: int64(len(rows)), Page: req.Page, Limit: req.Limit}
+
+	page, hasMore := paginate(len(rows), req.Page, req.Limit)
+	for _, row := range rows[page.from:page.to] {
+		comments.Items = append(comments.Items, row.entity())
+	}
+	comments.Count = int64(len(comments.Items))
+	comments.HasMore = hasMore
+
+	return &comments, nil
+}
+
+// entity returns a copy of the comment with its timestamps formatted like scanComment does.
+func (m *memoryComment) entity() *entity.Comment {
+	comment := m.Comment
+	comment.CreatedAt = m.createdAt.String()
+	comment.UpdatedAt = ""
+
+	if m.updatedAt != nil {
+		comment.UpdatedAt = m.updatedAt.String()
+	}
+
+	return &comment
+}
@@ -0,0 +1,306 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// _commentColumns are the columns scanned by scanComment.
+const _commentColumns = `
+		id,
+		post_id,
+		parent_id,
+		user_id,
+		api_key_id,
+		content,
+		created_at,
+		updated_at
+		`
+
+// _commentThread selects the ids of a comment and all of its replies.
+const _commentThread = `WITH RECURSIVE thread AS (
+		SELECT id FROM comments WHERE id = ? AND post_id = ?
+		UNION ALL
+		SELECT c.id FROM comments c JOIN thread t ON c.parent_id = t.id
+	)`
+
+// CommentRepo -.
+type CommentRepo struct {
+	*postgres.Postgres
+}
+
+// NewComment -.
+func NewComment(pg *postgres.Postgres) *CommentRepo {
+	return &CommentRepo{pg}
+}
+
+// Create Comment inserts the comment and bumps the post's comments_count in one transaction.
+// The post must exist and not be deleted -.
+func (c *CommentRepo) Create(ctx context.Context, req *entity.Comment) (*entity.Comment, error) {
+	if req.Id == "" {
+		req.Id = uuid.New().String()
+	}
+
+	var comment *entity.Comment
+
+	err := c.WithinTx(ctx, func(ctx context.Context) error {
+		if err := c.lockPost(ctx, req.PostId); err != nil {
+			return fmt.Errorf("c.lockPost: %w", err)
+		}
+
+		var parentId interface{}
+		if req.ParentId != "" {
+			if err := c.checkParent(ctx, req.PostId, req.ParentId); err != nil {
+				return fmt.Errorf("c.checkParent: %w", err)
+			}
+
+			parentId = req.ParentId
+		}
+
+		var apiKeyId interface{}
+		if req.APIKeyId != "" {
+			apiKeyId = req.APIKeyId
+		}
+
+		q, args, err := c.Builder.Insert("comments").
+			Columns("id", "post_id", "parent_id", "user_id", "api_key_id", "content", "created_at").
+			Values(req.Id, req.PostId, parentId, req.UserId, apiKeyId, req.Content, time.Now()).
+			Suffix("RETURNING " + _commentColumns).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("c.Builder: %w", err)
+		}
+
+		comment, err = scanComment(c.Querier(ctx).QueryRow(ctx, q, args...))
+		if err != nil {
+			return fmt.Errorf("row.Scan: %w", translateError(err))
+		}
+
+		if err := c.addToCount(ctx, req.PostId, 1); err != nil {
+			return fmt.Errorf("c.addToCount: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - CreateComment - c.WithinTx: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Get Comment -.
+func (c *CommentRepo) Get(ctx context.Context, postId, id string) (*entity.Comment, error) {
+	q, args, err := c.Builder.Select(_commentColumns).From("comments").
+		Where(squirrel.Eq{"id": id, "post_id": postId}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - GetComment - c.Builder: %w", err)
+	}
+
+	comment, err := scanComment(c.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - GetComment row.Scan: %w", translateError(err))
+	}
+
+	return comment, nil
+}
+
+// Update Comment changes the content of the comment -.
+func (c *CommentRepo) Update(ctx context.Context, req *entity.Comment) (*entity.Comment, error) {
+	q, args, err := c.Builder.Update("comments").
+		Set("content", req.Content).
+		Set("updated_at", time.Now()).
+		Where(squirrel.Eq{"id": req.Id, "post_id": req.PostId}).
+		Suffix("RETURNING " + _commentColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - UpdateComment - c.Builder: %w", err)
+	}
+
+	comment, err := scanComment(c.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - UpdateComment row.Scan: %w", translateError(err))
+	}
+
+	return comment, nil
+}
+
+// Delete Comment removes the comment and its whole reply thread, taking them
+// off the post's comments_count in the same transaction -.
+func (c *CommentRepo) Delete(ctx context.Context, postId, id string) error {
+	err := c.WithinTx(ctx, func(ctx context.Context) error {
+		if err := c.lockPost(ctx, postId); err != nil {
+			return fmt.Errorf("c.lockPost: %w", err)
+		}
+
+		q, args, err := c.Builder.Delete("comments").
+			Prefix(_commentThread, id, postId).
+			Where("id IN (SELECT id FROM thread)").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("c.Builder: %w", err)
+		}
+
+		tag, err := c.Querier(ctx).Exec(ctx, q, args...)
+		if err != nil {
+			return fmt.Errorf("Exec: %w", translateError(err))
+		}
+		if tag.RowsAffected() == 0 {
+			return translateError(pgx.ErrNoRows)
+		}
+
+		if err := c.addToCount(ctx, postId, -tag.RowsAffected()); err != nil {
+			return fmt.Errorf("c.addToCount: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("CommentRepo - DeleteComment - c.WithinTx: %w", err)
+	}
+
+	return nil
+}
+
+// List Comments of a post, oldest first -.
+func (c *CommentRepo) List(ctx context.Context, req *entity.CommentFilter) (*entity.Comments, error) {
+	where := squirrel.Eq{"post_id": req.PostId}
+	if req.ParentId != "" {
+		where["parent_id"] = req.ParentId
+	}
+
+	q, args, err := c.Builder.Select(_commentColumns).From("comments").
+		Where(where).
+		OrderBy("created_at ASC", "id ASC").
+		Offset(uint64((req.Page - 1) * req.Limit)).
+		Limit(uint64(req.Limit) + 1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - ListComments - c.Builder: %w", err)
+	}
+
+	rows, err := c.Querier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - ListComments - c.Querier.Query: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	comments := entity.Comments{Page: req.Page, Limit: req.Limit}
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("CommentRepo - ListComments row.Scan: %w", translateError(err))
+		}
+
+		comments.Items = append(comments.Items, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("CommentRepo - ListComments rows.Err: %w", translateError(err))
+	}
+
+	if int64(len(comments.Items)) > req.Limit {
+		comments.HasMore = true
+		comments.Items = comments.Items[:req.Limit]
+	}
+	comments.Count = int64(len(comments.Items))
+
+	q, args, err = c.Builder.Select("COUNT(*)").From("comments").Where(where).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("CommentRepo - ListComments - c.Builder: %w", err)
+	}
+	if err := c.Querier(ctx).QueryRow(ctx, q, args...).Scan(&comments.Total); err != nil {
+		return nil, fmt.Errorf("CommentRepo - ListComments count row.Scan: %w", translateError(err))
+	}
+
+	return &comments, nil
+}
+
+// lockPost locks the post row for the rest of the transaction, so comments_count changes
+// on the same post are serialized. Missing and soft-deleted posts are not found.
+func (c *CommentRepo) lockPost(ctx context.Context, postId string) error {
+	q, args, err := c.Builder.Select("id").From("posts").
+		Where(squirrel.Eq{"id": postId, "deleted_at": nil}).Suffix("FOR UPDATE").ToSql()
+	if err != nil {
+		return fmt.Errorf("c.Builder: %w", err)
+	}
+
+	if err := c.Querier(ctx).QueryRow(ctx, q, args...).Scan(&postId); err != nil {
+		return fmt.Errorf("row.Scan: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// checkParent makes sure a reply answers a comment of the same post.
+func (c *CommentRepo) checkParent(ctx context.Context, postId, parentId string) error {
+	q, args, err := c.Builder.Select("1").From("comments").
+		Where(squirrel.Eq{"id": parentId, "post_id": postId}).ToSql()
+	if err != nil {
+		return fmt.Errorf("c.Builder: %w", err)
+	}
+
+	var exists int
+	if err := c.Querier(ctx).QueryRow(ctx, q, args...).Scan(&exists); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			v := &entity.ValidationError{}
+			v.Add("parent_id", entity.CodeNotFound)
+
+			return v
+		}
+
+		return fmt.Errorf("row.Scan: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// addToCount adjusts the post's comments_count by delta.
+func (c *CommentRepo) addToCount(ctx context.Context, postId string, delta int64) error {
+	q, args, err := c.Builder.Update("posts").
+		Set("comments_count", squirrel.Expr("comments_count + ?", delta)).
+		Where(squirrel.Eq{"id": postId}).ToSql()
+	if err != nil {
+		return fmt.Errorf("c.Builder: %w", err)
+	}
+
+	if _, err := c.Querier(ctx).Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("Exec: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// scanComment scans the columns of _commentColumns.
+func scanComment(row pgx.Row) (*entity.Comment, error) {
+	var (
+		comment   entity.Comment
+		parentId  sql.NullString
+		apiKeyId  sql.NullString
+		createdAt time.Time
+		updatedAt sql.NullTime
+	)
+
+	if err := row.Scan(&comment.Id, &comment.PostId, &parentId, &comment.UserId, &apiKeyId,
+		&comment.Content, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	comment.ParentId = parentId.String
+	comment.APIKeyId = apiKeyId.String
+	comment.CreatedAt = createdAt.String()
+	if updatedAt.Valid {
+		comment.UpdatedAt = updatedAt.Time.String()
+	}
+
+	return &comment, nil
+}
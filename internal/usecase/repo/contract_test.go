@@ -0,0 +1,125 @@
+package repo_test
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase/repo"
+	"fourth-exam/post-service-clean-arch/internal/usecase/repo/repotest"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryContract(t *testing.T) {
+	t.Parallel()
+
+	run := func(t *testing.T, test repotest.Test) {
+		t.Parallel()
+
+		posts := repo.NewMemory()
+
+		test(context.Background(), t, repotest.Env{
+			Posts:    posts,
+			Comments: repo.NewMemoryComment(posts),
+			APIKeys:  repo.NewMemoryAPIKey(posts),
+			UserId:   uuid.NewString(),
+		})
+	}
+
+	repotest.PostRepo(t, run)
+	repotest.CommentRepo(t, run)
+	repotest.APIKeyRepo(t, run)
+}
+
+// TestPostgresContract runs the contract against the disposable database at
+// PG_URL, writing as PG_TEST_USER_ID, an existing user. The posts each test
+// creates are deleted after it.
+func TestPostgresContract(t *testing.T) {
+	url, userId := os.Getenv("PG_URL"), os.Getenv("PG_TEST_USER_ID")
+	if url == "" || userId == "" {
+		t.Skip("PG_URL and PG_TEST_USER_ID are not set")
+	}
+
+	pg, err := postgres.New(url)
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	env := repotest.Env{
+		Posts:    repo.New(pg),
+		Comments: repo.NewComment(pg),
+		APIKeys:  repo.NewAPIKey(pg),
+		UserId:   userId,
+	}
+
+	run := func(t *testing.T, test repotest.Test) {
+		start := time.Now()
+		t.Cleanup(func() {
+			_, err := pg.Pool.Exec(context.Background(),
+				"DELETE FROM posts WHERE user_id = $1 AND created_at >= $2", userId, start)
+			require.NoError(t, err)
+
+			_, err = pg.Pool.Exec(context.Background(), "DELETE FROM api_keys WHERE created_at >= $1", start)
+			require.NoError(t, err)
+		})
+
+		test(context.Background(), t, env)
+	}
+
+	repotest.PostRepo(t, run)
+	repotest.CommentRepo(t, run)
+	repotest.APIKeyRepo(t, run)
+}
+
+// TestPostgresReindex checks a post is searchable in the search language
+// once re-indexed after the language changed.
+func TestPostgresReindex(t *testing.T) {
+	url, userId := os.Getenv("PG_URL"), os.Getenv("PG_TEST_USER_ID")
+	if url == "" || userId == "" {
+		t.Skip("PG_URL and PG_TEST_USER_ID are not set")
+	}
+
+	pg, err := postgres.New(url)
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	var (
+		ctx      = context.Background()
+		category = "reindex" + uuid.NewString()[:8]
+		simple   = repo.New(pg, repo.SearchLanguage("simple"))
+		english  = repo.New(pg, repo.SearchLanguage("english"))
+	)
+
+	post, err := simple.Create(ctx, &entity.Post{
+		UserId: userId, Title: "Running", Content: "Running posts", Category: category, Status: entity.StatusPublished,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pg.Pool.Exec(context.Background(), "DELETE FROM posts WHERE id = $1", post.Id)
+		require.NoError(t, err)
+	})
+
+	search := func() int64 {
+		res, err := english.Search(ctx, &entity.SearchFilter{
+			GetListFilter: entity.GetListFilter{Categories: []string{category}, Page: 1, Limit: 10},
+			Query:         "run",
+		})
+		require.NoError(t, err)
+
+		return res.Count
+	}
+
+	require.Zero(t, search(), "simple doesn't stem running to run")
+
+	reindexed, err := english.Reindex(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, reindexed, int64(1))
+	require.Equal(t, int64(1), search())
+
+	reindexed, err = english.Reindex(ctx)
+	require.NoError(t, err)
+	require.Zero(t, reindexed)
+}
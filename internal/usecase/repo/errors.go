@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// Postgres error codes translated by translateError.
+const (
+	_foreignKeyViolation  = "23503"
+	_uniqueViolation      = "23505"
+	_checkViolation       = "23514"
+	_invalidTextRepresent = "22P02"
+)
+
+// translateError wraps pgx/pgconn errors with the matching entity error,
+// keeping the original error in the chain. Unknown errors are returned as is.
+func translateError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", entity.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case _uniqueViolation:
+		return fmt.Errorf("%w: %w", entity.ErrConflict, err)
+	case _foreignKeyViolation, _checkViolation, _invalidTextRepresent:
+		return fmt.Errorf("%w: %w", entity.ErrValidation, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	t.Parallel()
+
+	other := errors.New("connection reset")
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no rows", pgx.ErrNoRows, entity.ErrNotFound},
+		{"wrapped no rows", fmt.Errorf("row.Scan: %w", pgx.ErrNoRows), entity.ErrNotFound},
+		{"unique violation", &pgconn.PgError{Code: _uniqueViolation}, entity.ErrConflict},
+		{"foreign key violation", &pgconn.PgError{Code: _foreignKeyViolation}, entity.ErrValidation},
+		{"check violation", &pgconn.PgError{Code: _checkViolation}, entity.ErrValidation},
+		{"invalid text representation", &pgconn.PgError{Code: _invalidTextRepresent}, entity.ErrValidation},
+		{"other postgres error", &pgconn.PgError{Code: "40001"}, nil},
+		{"other error", other, nil},
+	}
+
+	domain := []error{entity.ErrNotFound, entity.ErrConflict, entity.ErrValidation}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := translateError(tc.err)
+			require.ErrorIs(t, got, tc.err, "the original error stays in the chain")
+
+			for _, d := range domain {
+				require.Equal(t, d == tc.want, errors.Is(got, d), d)
+			}
+
+			if tc.want == nil {
+				require.Same(t, tc.err, got, "unknown errors are returned as is")
+			}
+		})
+	}
+
+	require.NoError(t, translateError(nil))
+}
@@ -0,0 +1,12 @@
+package repo
+
+// Option -.
+type Option func(*PostRepo)
+
+// SearchLanguage sets the text search configuration posts are indexed and
+// searched in; see Reindex.
+func SearchLanguage(lang string) Option {
+	return func(p *PostRepo) {
+		p.searchLanguage = lang
+	}
+}
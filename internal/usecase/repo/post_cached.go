@@ -0,0 +1,227 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/cache"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+)
+
+const _postCacheKeyPrefix = "post:"
+
+var _postCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "post_cache_lookups_total",
+	Help: "Post cache lookups by result: hit, miss or error.",
+}, []string{"result"})
+
+// CachedPostRepo is a PostRepo reading posts through a cache. Posts are
+// evicted when changed through it; changes made elsewhere, such as
+// comments_count by CommentRepo, show once the cached post expires.
+type CachedPostRepo struct {
+	usecase.PostRepo
+
+	cache cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+
+	// gens holds the generation of each post being read into the cache: it
+	// starts at zero and is bumped whenever the post is evicted meanwhile.
+	mu   sync.Mutex
+	gens map[string]uint64
+}
+
+// NewCached -.
+func NewCached(next usecase.PostRepo, c cache.Cache, ttl time.Duration) *CachedPostRepo {
+	return &CachedPostRepo{PostRepo: next, cache: c, ttl: ttl, gens: make(map[string]uint64)}
+}
+
+// Get returns the cached post, or reads it and caches it. Concurrent misses
+// for a post share one read. Reads within a transaction bypass the cache, as
+// they may see changes not committed yet.
+func (r *CachedPostRepo) Get(ctx context.Context, id string) (*entity.Post, error) {
+	if postgres.InTx(ctx) {
+		return r.PostRepo.Get(ctx, id)
+	}
+
+	key := _postCacheKeyPrefix + id
+
+	data, ok, err := r.cache.Get(ctx, key)
+	switch {
+	case err != nil:
+		_postCacheLookups.WithLabelValues("error").Inc()
+	case ok:
+		_postCacheLookups.WithLabelValues("hit").Inc()
+
+		return decodePost(data)
+	default:
+		_postCacheLookups.WithLabelValues("miss").Inc()
+	}
+
+	// The read is shared by every caller missing the post meanwhile, so it
+	// must not fail because the one that started it went away; each caller
+	// only stops waiting when its own ctx is done.
+	ch := r.group.DoChan(key, func() (interface{}, error) {
+		ctx := context.WithoutCancel(ctx)
+
+		r.beginFill(key)
+		defer r.endFill(key)
+
+		post, err := r.PostRepo.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(post)
+		if err != nil {
+			return nil, fmt.Errorf("CachedPostRepo - Get - json.Marshal: %w", err)
+		}
+
+		// Scheduled posts get published by PublishDue, which doesn't say which.
+		if post.Status != entity.StatusScheduled {
+			r.fill(ctx, key, data)
+		}
+
+		return data, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("CachedPostRepo - Get: %w", ctx.Err())
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		// Each caller decodes its own copy, as callers may change the post.
+		return decodePost(res.Val.([]byte))
+	}
+}
+
+// Update -.
+func (r *CachedPostRepo) Update(ctx context.Context, req *entity.Post) (*entity.Post, error) {
+	defer r.evict(ctx, req.Id)
+
+	return r.PostRepo.Update(ctx, req)
+}
+
+// Patch -.
+func (r *CachedPostRepo) Patch(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error) {
+	defer r.evict(ctx, id)
+
+	return r.PostRepo.Patch(ctx, id, patch)
+}
+
+// Delete -.
+func (r *CachedPostRepo) Delete(ctx context.Context, id string, version int64) error {
+	defer r.evict(ctx, id)
+
+	return r.PostRepo.Delete(ctx, id, version)
+}
+
+// Restore -.
+func (r *CachedPostRepo) Restore(ctx context.Context, id string) error {
+	defer r.evict(ctx, id)
+
+	return r.PostRepo.Restore(ctx, id)
+}
+
+// Transition -.
+func (r *CachedPostRepo) Transition(ctx context.Context, id string, change *entity.StatusChange) (*entity.Post, error) {
+	defer r.evict(ctx, id)
+
+	return r.PostRepo.Transition(ctx, id, change)
+}
+
+// React -.
+func (r *CachedPostRepo) React(ctx context.Context, req *entity.Reaction) error {
+	defer r.evict(ctx, req.PostId)
+
+	return r.PostRepo.React(ctx, req)
+}
+
+// Unreact -.
+func (r *CachedPostRepo) Unreact(ctx context.Context, postId, userId string) error {
+	defer r.evict(ctx, postId)
+
+	return r.PostRepo.Unreact(ctx, postId, userId)
+}
+
+// AddViews -.
+func (r *CachedPostRepo) AddViews(ctx context.Context, views map[string]int64) error {
+	ids := make([]string, 0, len(views))
+	for id := range views {
+		ids = append(ids, id)
+	}
+	defer r.evict(ctx, ids...)
+
+	return r.PostRepo.AddViews(ctx, views)
+}
+
+// evict removes the posts from the cache once the transaction in ctx, if
+// any, commits; until then other readers must keep seeing the old posts.
+// Eviction errors are ignored: the posts expire anyway.
+func (r *CachedPostRepo) evict(ctx context.Context, ids ...string) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = _postCacheKeyPrefix + id
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	postgres.AfterCommit(ctx, func() {
+		r.mu.Lock()
+		for _, key := range keys {
+			if _, ok := r.gens[key]; ok {
+				r.gens[key]++
+			}
+		}
+		r.mu.Unlock()
+
+		_ = r.cache.Delete(ctx, keys...)
+	})
+}
+
+// beginFill registers a read of key into the cache. Singleflight runs one
+// read per key at a time.
+func (r *CachedPostRepo) beginFill(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.gens[key] = 0
+}
+
+// fill caches data unless the post was evicted since its read began: the
+// data may then predate the change evicting it.
+func (r *CachedPostRepo) fill(ctx context.Context, key string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.gens[key] == 0 {
+		_ = r.cache.Set(ctx, key, data, r.ttl)
+	}
+}
+
+// endFill -.
+func (r *CachedPostRepo) endFill(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.gens, key)
+}
+
+func decodePost(data []byte) (*entity.Post, error) {
+	post := &entity.Post{}
+	if err := json.Unmarshal(data, post); err != nil {
+		return nil, fmt.Errorf("CachedPostRepo - Get - json.Unmarshal: %w", err)
+	}
+
+	return post, nil
+}
@@ -0,0 +1,252 @@
+package repo_test
+
+import (
+	"context"
+	"errors"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/internal/usecase/repo"
+	"fourth-exam/post-service-clean-arch/pkg/cache"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRepo is a PostRepo counting its reads, which wait for release
+// once done when it is set.
+type countingRepo struct {
+	usecase.PostRepo
+
+	reads   atomic.Int32
+	release chan struct{}
+}
+
+func (r *countingRepo) Get(ctx context.Context, id string) (*entity.Post, error) {
+	r.reads.Add(1)
+
+	post, err := r.PostRepo.Get(ctx, id)
+
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return post, err
+}
+
+// cachedRepo returns a cached repository over a memory one holding a post.
+func cachedRepo(t *testing.T) (*repo.CachedPostRepo, *countingRepo, *entity.Post) {
+	t.Helper()
+
+	next := &countingRepo{PostRepo: repo.NewMemory()}
+
+	post, err := next.Create(context.Background(), &entity.Post{
+		UserId:   uuid.NewString(),
+		Title:    "Title",
+		Content:  "Content",
+		Category: "cached",
+		Status:   entity.StatusPublished,
+	})
+	require.NoError(t, err)
+
+	return repo.NewCached(next, cache.NewLRU(10), time.Minute), next, post
+}
+
+func TestCachedGet(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx                = context.Background()
+		cached, next, post = cachedRepo(t)
+		title              = "Changed"
+	)
+
+	got, err := cached.Get(ctx, post.Id)
+	require.NoError(t, err)
+	require.Equal(t, post.Title, got.Title)
+	require.Equal(t, int32(1), next.reads.Load(), "a miss reads the post")
+
+	got.Title = "Mutated"
+
+	got, err = cached.Get(ctx, post.Id)
+	require.NoError(t, err)
+	require.Equal(t, post.Title, got.Title, "callers get their own copy")
+	require.Equal(t, int32(1), next.reads.Load(), "a hit doesn't")
+
+	_, err = cached.Patch(ctx, post.Id, &entity.PostPatch{Title: &title, Version: post.Version})
+	require.NoError(t, err)
+
+	got, err = cached.Get(ctx, post.Id)
+	require.NoError(t, err)
+	require.Equal(t, title, got.Title, "changes evict the post")
+	require.Equal(t, int32(2), next.reads.Load())
+
+	_, err = cached.Get(ctx, uuid.NewString())
+	require.ErrorIs(t, err, entity.ErrNotFound)
+	_, err = cached.Get(ctx, uuid.NewString())
+	require.ErrorIs(t, err, entity.ErrNotFound)
+	require.Equal(t, int32(4), next.reads.Load(), "errors aren't cached")
+}
+
+func TestCachedGetScheduled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx    = context.Background()
+		next   = &countingRepo{PostRepo: repo.NewMemory()}
+		cached = repo.NewCached(next, cache.NewLRU(10), time.Minute)
+	)
+
+	post, err := next.Create(ctx, &entity.Post{
+		UserId:      uuid.NewString(),
+		Title:       "Title",
+		Content:     "Content",
+		Category:    "cached",
+		Status:      entity.StatusScheduled,
+		PublishedAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = cached.Get(ctx, post.Id)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), next.reads.Load(), "scheduled posts aren't cached")
+}
+
+// TestPostgresCachedPostRepo checks the cache against transactions on the
+// disposable database at PG_URL, writing as PG_TEST_USER_ID.
+func TestPostgresCachedPostRepo(t *testing.T) {
+	url, userId := os.Getenv("PG_URL"), os.Getenv("PG_TEST_USER_ID")
+	if url == "" || userId == "" {
+		t.Skip("PG_URL and PG_TEST_USER_ID are not set")
+	}
+
+	pg, err := postgres.New(url)
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	var (
+		ctx    = context.Background()
+		next   = &countingRepo{PostRepo: repo.New(pg)}
+		cached = repo.NewCached(next, cache.NewLRU(10), time.Minute)
+	)
+
+	post, err := next.Create(ctx, &entity.Post{
+		UserId: userId, Title: "Title", Content: "Content", Category: "cached", Status: entity.StatusPublished,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pg.Pool.Exec(context.Background(), "DELETE FROM posts WHERE id = $1", post.Id)
+		require.NoError(t, err)
+	})
+
+	title := func() string {
+		got, err := cached.Get(ctx, post.Id)
+		require.NoError(t, err)
+
+		return got.Title
+	}
+
+	require.Equal(t, "Title", title())
+
+	errRollback := errors.New("rollback")
+	err = pg.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		changed := "Rolled back"
+		_, err := cached.Patch(ctx, post.Id, &entity.PostPatch{Title: &changed, Version: post.Version})
+		require.NoError(t, err)
+
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+	require.Equal(t, int32(1), next.reads.Load(), "a rolled back change doesn't evict the post")
+
+	err = pg.TxManager.WithinTx(ctx, func(txCtx context.Context) error {
+		changed := "Committed"
+		_, err := cached.Patch(txCtx, post.Id, &entity.PostPatch{Title: &changed, Version: post.Version})
+		require.NoError(t, err)
+
+		got, err := cached.Get(txCtx, post.Id)
+		require.NoError(t, err)
+		require.Equal(t, changed, got.Title, "reads in the transaction bypass the cache")
+		require.Equal(t, "Title", title(), "others see the post cached until the commit")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	reads := next.reads.Load()
+	require.Equal(t, "Committed", title(), "the commit evicts the post")
+	require.Equal(t, reads+1, next.reads.Load())
+}
+
+func TestCachedGetSharedReadOutlivesCaller(t *testing.T) {
+	t.Parallel()
+
+	cached, next, post := cachedRepo(t)
+	next.release = make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first, second := make(chan error), make(chan error)
+
+	go func() {
+		_, err := cached.Get(ctx, post.Id)
+		first <- err
+	}()
+	require.Eventually(t, func() bool { return next.reads.Load() == 1 }, time.Second, time.Millisecond)
+
+	go func() {
+		_, err := cached.Get(context.Background(), post.Id)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-first, context.Canceled, "a caller stops waiting when its ctx is done")
+
+	close(next.release)
+	require.NoError(t, <-second, "the read the cancelled caller started goes on for the others")
+	require.Equal(t, int32(1), next.reads.Load())
+
+	got, err := cached.Get(context.Background(), post.Id)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), next.reads.Load(), "the shared read filled the cache")
+	require.Equal(t, post.Id, got.Id)
+}
+
+func TestCachedGetSkipsFillEvictedMeanwhile(t *testing.T) {
+	t.Parallel()
+
+	cached, next, post := cachedRepo(t)
+	next.release = make(chan struct{})
+
+	read := make(chan *entity.Post)
+
+	go func() {
+		got, err := cached.Get(context.Background(), post.Id)
+		assert.NoError(t, err)
+		read <- got
+	}()
+	require.Eventually(t, func() bool { return next.reads.Load() == 1 }, time.Second, time.Millisecond)
+
+	title := "Changed"
+	_, err := cached.Patch(context.Background(), post.Id, &entity.PostPatch{Title: &title, Version: post.Version})
+	require.NoError(t, err)
+
+	close(next.release)
+	require.Equal(t, "Title", (<-read).Title, "the read began before the change")
+
+	got, err := cached.Get(context.Background(), post.Id)
+	require.NoError(t, err)
+	require.Equal(t, title, got.Title, "the read began before the change isn't cached")
+	require.Equal(t, int32(2), next.reads.Load())
+}
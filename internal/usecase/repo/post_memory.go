@@ -0,0 +1,929 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// MemoryPostRepo is a PostRepo keeping posts in memory, for running the
+// service and tests without a database. It behaves like PostRepo, except
+// that Search matches whole words without stemming and highlights the
+// whole content, and that it has no users table to check user ids against.
+type MemoryPostRepo struct {
+	*memoryStore
+}
+
+// memoryStore holds the rows of the in-memory repositories, which share it
+// the way the Postgres ones share the database.
+type memoryStore struct {
+	mu        sync.RWMutex
+	posts     map[string]*memoryPost
+	revisions map[string]map[int64]*entity.Revision
+	comments  map[string]*memoryComment
+	apiKeys   map[string]*memoryAPIKey
+}
+
+// memoryPost is a posts row. The timestamp strings of Post are filled in
+// from the typed fields when the post is read.
+type memoryPost struct {
+	entity.Post
+
+	createdAt   time.Time
+	updatedAt   *time.Time
+	deletedAt   *time.Time
+	publishedAt *time.Time
+	reactions   map[string]string
+}
+
+// NewMemory -.
+func NewMemory() *MemoryPostRepo {
+	return &MemoryPostRepo{&memoryStore{
+		posts:     make(map[string]*memoryPost),
+		revisions: make(map[string]map[int64]*entity.Revision),
+		comments:  make(map[string]*memoryComment),
+		apiKeys:   make(map[string]*memoryAPIKey),
+	}}
+}
+
+// WithinTx runs fn as is: each method of the in-memory repositories is
+// atomic on its own, but a sequence of them is not.
+func (r *MemoryPostRepo) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// Create post -.
+func (r *MemoryPostRepo) Create(_ context.Context, req *entity.Post) (*entity.Post, error) {
+	if req.Id == "" {
+		req.Id = uuid.New().String()
+	}
+
+	if err := checkIds(req.Id, req.UserId); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - CreatePost: %w", err)
+	}
+
+	var publishedAt *time.Time
+	if req.PublishedAt != "" {
+		at, err := time.Parse(time.RFC3339Nano, req.PublishedAt)
+		if err != nil {
+			return nil, fmt.Errorf("MemoryPostRepo - CreatePost - time.Parse: %w", entity.ErrValidation)
+		}
+
+		publishedAt = timestamp(at)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.posts[req.Id]; ok {
+		return nil, fmt.Errorf("MemoryPostRepo - CreatePost: post %s exists: %w", req.Id, entity.ErrConflict)
+	}
+
+	row := &memoryPost{
+		Post:        *req,
+		createdAt:   *timestamp(time.Now()),
+		publishedAt: publishedAt,
+		reactions:   make(map[string]string),
+	}
+	row.Likes, row.Dislikes, row.Views, row.CommentsCount, row.Version = 0, 0, 0, 0, 1
+	row.Tags = entity.NormalizeTags(req.Tags)
+	r.posts[req.Id] = row
+
+	post := row.entity()
+	req.Likes, req.Dislikes, req.Views, req.CommentsCount, req.Version = 0, 0, 0, 0, 1
+	req.PublishedAt, req.CreatedAt, req.UpdatedAt = post.PublishedAt, post.CreatedAt, ""
+
+	return req, nil
+}
+
+// Get Post -.
+func (r *MemoryPostRepo) Get(_ context.Context, id string) (*entity.Post, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id is required: %w", entity.ErrValidation)
+	}
+
+	if err := checkIds(id); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - GetPost: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	row, err := r.live(id)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - GetPost: %w", err)
+	}
+
+	return row.entity(), nil
+}
+
+// Update Post overwrites the post if it is still at req.Version, saving the
+// replaced content as a revision -.
+func (r *MemoryPostRepo) Update(_ context.Context, req *entity.Post) (*entity.Post, error) {
+	if err := checkIds(req.Id, req.UserId); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - UpdatePost: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, err := r.atVersion(req.Id, req.Version)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - UpdatePost: %w", err)
+	}
+
+	r.snapshot(row)
+
+	row.UserId, row.Content, row.Title, row.Category = req.UserId, req.Content, req.Title, req.Category
+	row.Tags = entity.NormalizeTags(req.Tags)
+	row.Version++
+	row.updatedAt = timestamp(time.Now())
+
+	post := row.entity()
+	req.Likes, req.Dislikes, req.Views, req.CommentsCount = post.Likes, post.Dislikes, post.Views, post.CommentsCount
+	req.Version, req.Status = post.Version, post.Status
+	if post.PublishedAt != "" {
+		req.PublishedAt = post.PublishedAt
+	}
+	req.CreatedAt, req.UpdatedAt = post.CreatedAt, post.UpdatedAt
+
+	return req, nil
+}
+
+// Patch updates only the fields set in patch, if the post is still at
+// patch.Version, saving the replaced content as a revision -.
+func (r *MemoryPostRepo) Patch(_ context.Context, id string, patch *entity.PostPatch) (*entity.Post, error) {
+	if err := checkIds(id); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - PatchPost: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, err := r.atVersion(id, patch.Version)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - PatchPost: %w", err)
+	}
+
+	r.snapshot(row)
+
+	if patch.Title != nil {
+		row.Title = *patch.Title
+	}
+	if patch.Content != nil {
+		row.Content = *patch.Content
+	}
+	if patch.Category != nil {
+		row.Category = *patch.Category
+	}
+	if patch.Tags != nil {
+		row.Tags = entity.NormalizeTags(*patch.Tags)
+	}
+	row.Version++
+	row.updatedAt = timestamp(time.Now())
+
+	return row.entity(), nil
+}
+
+// Delete Post soft-deletes the post, if it is still at version -.
+func (r *MemoryPostRepo) Delete(_ context.Context, id string, version int64) error {
+	if id == "" {
+		return fmt.Errorf("id is required: %w", entity.ErrValidation)
+	}
+
+	if err := checkIds(id); err != nil {
+		return fmt.Errorf("MemoryPostRepo - DeletePost: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, err := r.atVersion(id, version)
+	if err != nil {
+		return fmt.Errorf("MemoryPostRepo - DeletePost: %w", err)
+	}
+
+	row.deletedAt = timestamp(time.Now())
+	row.Version++
+
+	return nil
+}
+
+// Restore Post clears the deletion of a soft-deleted post -.
+func (r *MemoryPostRepo) Restore(_ context.Context, id string) error {
+	if err := checkIds(id); err != nil {
+		return fmt.Errorf("MemoryPostRepo - RestorePost: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, ok := r.posts[id]
+	if !ok || row.deletedAt == nil {
+		return fmt.Errorf("MemoryPostRepo - RestorePost: %w", entity.ErrNotFound)
+	}
+
+	row.deletedAt = nil
+	row.Version++
+
+	return nil
+}
+
+// Transition moves the post to change.To, if it is still in change.From -.
+func (r *MemoryPostRepo) Transition(_ context.Context, id string, change *entity.StatusChange) (*entity.Post, error) {
+	if err := checkIds(id); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - TransitionPost: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, err := r.live(id)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - TransitionPost: %w", err)
+	}
+	if row.Status != change.From {
+		return nil, fmt.Errorf("MemoryPostRepo - TransitionPost: %w", entity.ErrVersionConflict)
+	}
+
+	row.Status = change.To
+	row.Version++
+	row.updatedAt = timestamp(time.Now())
+
+	switch {
+	case change.PublishedAt != nil:
+		row.publishedAt = timestamp(*change.PublishedAt)
+	case change.ClearPublishedAt:
+		row.publishedAt = nil
+	}
+
+	return row.entity(), nil
+}
+
+// PublishDue publishes the scheduled posts due at now -.
+func (r *MemoryPostRepo) PublishDue(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var published int64
+	for _, row := range r.posts {
+		if row.deletedAt == nil && row.Status == entity.StatusScheduled &&
+			row.publishedAt != nil && !row.publishedAt.After(now) {
+			row.Status = entity.StatusPublished
+			row.Version++
+			published++
+		}
+	}
+
+	return published, nil
+}
+
+// Purge hard-deletes posts soft-deleted more than retention ago, with
+// everything that belongs to them -.
+func (r *MemoryPostRepo) Purge(_ context.Context, retention time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-retention)
+
+	var purged int64
+	for id, row := range r.posts {
+		if row.deletedAt != nil && row.deletedAt.Before(cutoff) {
+			delete(r.posts, id)
+			delete(r.revisions, id)
+			for commentId, comment := range r.comments {
+				if comment.PostId == id {
+					delete(r.comments, commentId)
+				}
+			}
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// React sets the user's reaction on a post, keeping the likes/dislikes counters in sync -.
+func (r *MemoryPostRepo) React(_ context.Context, req *entity.Reaction) error {
+	return r.setReaction(req.PostId, req.UserId, req.Reaction)
+}
+
+// Unreact removes the user's reaction from a post -.
+func (r *MemoryPostRepo) Unreact(_ context.Context, postId, userId string) error {
+	return r.setReaction(postId, userId, "")
+}
+
+func (r *MemoryPostRepo) setReaction(postId, userId, reaction string) error {
+	if err := checkIds(postId, userId); err != nil {
+		return fmt.Errorf("MemoryPostRepo - setReaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	row, err := r.live(postId)
+	if err != nil {
+		return fmt.Errorf("MemoryPostRepo - setReaction: %w", err)
+	}
+
+	prevLikes, prevDislikes := reactionCounts(row.reactions[userId])
+	likes, dislikes := reactionCounts(reaction)
+
+	if reaction == "" {
+		delete(row.reactions, userId)
+	} else {
+		row.reactions[userId] = reaction
+	}
+	row.Likes += likes - prevLikes
+	row.Dislikes += dislikes - prevDislikes
+
+	return nil
+}
+
+// AddViews adds views to the counters of the posts -.
+func (r *MemoryPostRepo) AddViews(_ context.Context, views map[string]int64) error {
+	for id := range views {
+		if err := checkIds(id); err != nil {
+			return fmt.Errorf("MemoryPostRepo - AddViews: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, n := range views {
+		if row, ok := r.posts[id]; ok && row.deletedAt == nil {
+			row.Views += n
+		}
+	}
+
+	return nil
+}
+
+// List Posts -.
+func (r *MemoryPostRepo) List(_ context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
+	less, err := postOrder(req.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - ListPost - postOrder: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rows, err := r.filter(req, func(*memoryPost) bool { return true })
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - ListPost: %w", err)
+	}
+	total := int64(len(rows))
+
+	sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+
+	posts := entity.Posts{Page: req.Page, Limit: req.Limit}
+
+	page, hasMore := paginate(len(rows), req.Page, req.Limit)
+	for _, row := range rows[page.from:page.to] {
+		posts.Items = append(posts.Items, row.entity())
+	}
+	posts.Count = int64(len(posts.Items))
+	posts.HasMore = hasMore
+
+	if req.WithTotal {
+		posts.Total = &total
+	}
+
+	return &posts, nil
+}
+
+// ListByCursor returns the page of posts after (or, with req.Backward, before)
+// req.Keyset in created_at DESC, id DESC order -.
+func (r *MemoryPostRepo) ListByCursor(_ context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rows, err := r.filter(req, func(row *memoryPost) bool {
+		if req.Keyset == nil {
+			return true
+		}
+
+		cmp := row.createdAt.Compare(req.Keyset.CreatedAt)
+		if cmp == 0 {
+			cmp = strings.Compare(row.Id, req.Keyset.Id)
+		}
+
+		if req.Backward {
+			return cmp > 0
+		}
+
+		return cmp < 0
+	})
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - ListByCursor: %w", err)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		newer := rows[i].createdAt.After(rows[j].createdAt) ||
+			rows[i].createdAt.Equal(rows[j].createdAt) && rows[i].Id > rows[j].Id
+		if req.Backward {
+			return !newer
+		}
+
+		return newer
+	})
+
+	posts := entity.Posts{Limit: req.Limit}
+
+	page, hasMore := paginate(len(rows), 1, req.Limit)
+	for _, row := range rows[page.from:page.to] {
+		posts.Items = append(posts.Items, row.entity())
+	}
+	posts.HasMore = hasMore
+
+	if req.Backward {
+		for i, j := 0, len(posts.Items)-1; i < j; i, j = i+1, j-1 {
+			posts.Items[i], posts.Items[j] = posts.Items[j], posts.Items[i]
+		}
+	}
+	posts.Count = int64(len(posts.Items))
+
+	return &posts, nil
+}
+
+// Search returns the posts containing every word of the query, most
+// relevant first unless req.Sort says otherwise. Words prefixed with a dash
+// must not appear -.
+func (r *MemoryPostRepo) Search(_ context.Context, req *entity.SearchFilter) (*entity.SearchResults, error) {
+	less, err := postOrder(req.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - SearchPosts - postOrder: %w", err)
+	}
+
+	include, exclude := searchTerms(req.Query)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ranks := make(map[string]float32)
+
+	rows, err := r.filter(&req.GetListFilter, func(row *memoryPost) bool {
+		if len(include) == 0 {
+			return false
+		}
+
+		counts := wordCounts(row.Title + " " + row.Content)
+		for _, term := range exclude {
+			if counts[term] > 0 {
+				return false
+			}
+		}
+
+		var rank float32
+		for _, term := range include {
+			if counts[term] == 0 {
+				return false
+			}
+			rank += float32(counts[term])
+		}
+		ranks[row.Id] = rank
+
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - SearchPosts: %w", err)
+	}
+	total := int64(len(rows))
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if len(req.Sort) > 0 {
+			return less(rows[i], rows[j])
+		}
+
+		if ranks[rows[i].Id] != ranks[rows[j].Id] {
+			return ranks[rows[i].Id] > ranks[rows[j].Id]
+		}
+
+		return rows[i].Id < rows[j].Id
+	})
+
+	results := entity.SearchResults{Page: req.Page, Limit: req.Limit}
+
+	page, hasMore := paginate(len(rows), req.Page, req.Limit)
+	for _, row := range rows[page.from:page.to] {
+		results.Items = append(results.Items, &entity.SearchResult{
+			Post:     row.entity(),
+			Rank:     ranks[row.Id],
+			Headline: highlight(row.Content, include),
+		})
+	}
+	results.Count = int64(len(results.Items))
+	results.HasMore = hasMore
+
+	if req.WithTotal {
+		results.Total = &total
+	}
+
+	return &results, nil
+}
+
+// ListTags returns the tags of published posts with their usage counts, most used first -.
+func (r *MemoryPostRepo) ListTags(_ context.Context, req *entity.TagFilter) (*entity.Tags, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	uses := make(map[string]int64)
+	for _, row := range r.posts {
+		if row.deletedAt != nil || row.Status != entity.StatusPublished {
+			continue
+		}
+
+		for _, name := range row.Tags {
+			if strings.HasPrefix(name, req.Prefix) {
+				uses[name]++
+			}
+		}
+	}
+
+	tags := entity.Tags{}
+	for name, count := range uses {
+		tags.Items = append(tags.Items, &entity.Tag{Name: name, Count: count})
+	}
+
+	sort.Slice(tags.Items, func(i, j int) bool {
+		if tags.Items[i].Count != tags.Items[j].Count {
+			return tags.Items[i].Count > tags.Items[j].Count
+		}
+
+		return tags.Items[i].Name < tags.Items[j].Name
+	})
+
+	if int64(len(tags.Items)) > req.Limit {
+		tags.Items = tags.Items[:req.Limit]
+	}
+	tags.Count = int64(len(tags.Items))
+
+	return &tags, nil
+}
+
+// ListRevisions returns the saved revisions of a post, newest first -.
+func (r *MemoryPostRepo) ListRevisions(_ context.Context, req *entity.RevisionFilter) (*entity.Revisions, error) {
+	if err := checkIds(req.PostId); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - ListRevisions: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]*entity.Revision, 0, len(r.revisions[req.PostId]))
+	for _, revision := range r.revisions[req.PostId] {
+		all = append(all, revision)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Version > all[j].Version })
+
+	revisions := entity.Revisions{Page: req.Page, Limit: req.Limit}
+
+	page, hasMore := paginate(len(all), req.Page, req.Limit)
+	for _, revision := range all[page.from:page.to] {
+		revisions.Items = append(revisions.Items, copyRevision(revision))
+	}
+	revisions.Count = int64(len(revisions.Items))
+	revisions.HasMore = hasMore
+
+	return &revisions, nil
+}
+
+// GetRevision returns the saved revision of a post at version -.
+func (r *MemoryPostRepo) GetRevision(_ context.Context, postId string, version int64) (*entity.Revision, error) {
+	if err := checkIds(postId); err != nil {
+		return nil, fmt.Errorf("MemoryPostRepo - GetRevision: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revision, ok := r.revisions[postId][version]
+	if !ok {
+		return nil, fmt.Errorf("MemoryPostRepo - GetRevision: %w", entity.ErrNotFound)
+	}
+
+	return copyRevision(revision), nil
+}
+
+// live returns the post unless it is missing or soft-deleted.
+func (s *memoryStore) live(id string) (*memoryPost, error) {
+	row, ok := s.posts[id]
+	if !ok || row.deletedAt != nil {
+		return nil, entity.ErrNotFound
+	}
+
+	return row, nil
+}
+
+// atVersion returns the post if it is still at version, like the versioned
+// writes of PostRepo.
+func (s *memoryStore) atVersion(id string, version int64) (*memoryPost, error) {
+	row, err := s.live(id)
+	if err != nil {
+		return nil, err
+	}
+	if row.Version != version {
+		return nil, entity.ErrVersionConflict
+	}
+
+	return row, nil
+}
+
+// snapshot saves the post as it is now as a revision, unless one was saved
+// for its version already.
+func (s *memoryStore) snapshot(row *memoryPost) {
+	if s.revisions[row.Id] == nil {
+		s.revisions[row.Id] = make(map[int64]*entity.Revision)
+	}
+	if _, ok := s.revisions[row.Id][row.Version]; ok {
+		return
+	}
+
+	editedAt := row.createdAt
+	if row.updatedAt != nil {
+		editedAt = *row.updatedAt
+	}
+
+	s.revisions[row.Id][row.Version] = &entity.Revision{
+		PostId:   row.Id,
+		Version:  row.Version,
+		UserId:   row.UserId,
+		Title:    row.Title,
+		Content:  row.Content,
+		Category: row.Category,
+		Tags:     append([]string{}, row.Tags...),
+		EditedAt: editedAt.String(),
+	}
+}
+
+// filter returns the posts matching req and match, in no particular order.
+func (s *memoryStore) filter(req *entity.GetListFilter, match func(*memoryPost) bool) ([]*memoryPost, error) {
+	if req.UserId != "" {
+		if err := checkIds(req.UserId); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkIds(req.Ids...); err != nil {
+		return nil, err
+	}
+
+	var rows []*memoryPost
+	for _, row := range s.posts {
+		if matchesFilter(row, req) && match(row) {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// matchesFilter is listConditions for a single post.
+func matchesFilter(row *memoryPost, req *entity.GetListFilter) bool {
+	switch {
+	case req.UserId != "" && row.UserId != req.UserId,
+		len(req.Categories) > 0 && !contains(req.Categories, row.Category),
+		len(req.Ids) > 0 && !contains(req.Ids, row.Id),
+		req.TitlePrefix != "" && !strings.HasPrefix(strings.ToLower(row.Title), strings.ToLower(req.TitlePrefix)),
+		req.CreatedFrom != nil && row.createdAt.Before(*req.CreatedFrom),
+		req.CreatedTo != nil && !row.createdAt.Before(*req.CreatedTo),
+		req.UpdatedFrom != nil && (row.updatedAt == nil || row.updatedAt.Before(*req.UpdatedFrom)),
+		req.UpdatedTo != nil && (row.updatedAt == nil || !row.updatedAt.Before(*req.UpdatedTo)),
+		req.MinLikes != nil && row.Likes < *req.MinLikes,
+		req.MinViews != nil && row.Views < *req.MinViews,
+		len(req.Statuses) > 0 && !contains(req.Statuses, row.Status),
+		!req.IncludeDeleted && row.deletedAt != nil:
+		return false
+	}
+
+	if len(req.TagsAny) > 0 {
+		found := false
+		for _, tag := range req.TagsAny {
+			found = found || contains(row.Tags, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, tag := range req.TagsAll {
+		if !contains(row.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// postOrder is orderByClauses as a less function, with Postgres' NULL
+// ordering: missing updated_at sorts last ascending and first descending.
+func postOrder(fields []entity.SortField) (func(a, b *memoryPost) bool, error) {
+	if len(fields) == 0 {
+		fields = []entity.SortField{{Field: "created_at"}}
+	}
+
+	for _, f := range fields {
+		if _, ok := sortColumns[f.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q: %w", f.Field, entity.ErrValidation)
+		}
+	}
+
+	return func(a, b *memoryPost) bool {
+		for _, f := range fields {
+			cmp := compareField(a, b, f.Field)
+			if f.Desc {
+				cmp = -cmp
+			}
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+
+		return a.Id < b.Id
+	}, nil
+}
+
+// compareField compares a sortable field of two posts, missing values last.
+func compareField(a, b *memoryPost, field string) int {
+	switch field {
+	case "likes":
+		return compareInt(a.Likes, b.Likes)
+	case "views":
+		return compareInt(a.Views, b.Views)
+	case "title":
+		return strings.Compare(a.Title, b.Title)
+	case "updated_at":
+		switch {
+		case a.updatedAt == nil && b.updatedAt == nil:
+			return 0
+		case a.updatedAt == nil:
+			return 1
+		case b.updatedAt == nil:
+			return -1
+		default:
+			return a.updatedAt.Compare(*b.updatedAt)
+		}
+	default:
+		return a.createdAt.Compare(b.createdAt)
+	}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// pageBounds is the slice of the matching rows a page shows.
+type pageBounds struct {
+	from, to int
+}
+
+// paginate returns the bounds of page among n rows, and whether rows remain
+// after it, the way OFFSET and LIMIT+1 do.
+func paginate(n int, page, limit int64) (pageBounds, bool) {
+	from := int((page - 1) * limit)
+	from = min(max(from, 0), n)
+
+	to := min(from+int(max(limit, 0)), n)
+
+	return pageBounds{from: from, to: to}, to < n
+}
+
+// entity returns a copy of the post with its timestamps formatted like scanPost does.
+func (m *memoryPost) entity() *entity.Post {
+	post := m.Post
+	post.Tags = append([]string{}, m.Tags...)
+	post.CreatedAt = m.createdAt.String()
+	post.UpdatedAt, post.DeletedAt, post.PublishedAt = "", "", ""
+
+	if m.updatedAt != nil {
+		post.UpdatedAt = m.updatedAt.String()
+	}
+	if m.deletedAt != nil {
+		post.DeletedAt = m.deletedAt.String()
+	}
+	if m.publishedAt != nil {
+		post.PublishedAt = m.publishedAt.String()
+	}
+
+	return &post
+}
+
+func copyRevision(revision *entity.Revision) *entity.Revision {
+	c := *revision
+	c.Tags = append([]string{}, revision.Tags...)
+
+	return &c
+}
+
+// timestamp returns t as Postgres stores a timestamp: in UTC, to the microsecond.
+func timestamp(t time.Time) *time.Time {
+	t = t.UTC().Truncate(time.Microsecond)
+
+	return &t
+}
+
+// checkIds fails like Postgres does on malformed uuids.
+func checkIds(ids ...string) error {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("invalid uuid %q: %w", id, entity.ErrValidation)
+		}
+	}
+
+	return nil
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchTerms splits a query into the words that must and must not appear.
+func searchTerms(query string) (include, exclude []string) {
+	for _, field := range strings.Fields(query) {
+		negated := strings.HasPrefix(field, "-")
+
+		for _, word := range words(field) {
+			if negated {
+				exclude = append(exclude, word)
+			} else if word != "or" {
+				include = append(include, word)
+			}
+		}
+	}
+
+	return include, exclude
+}
+
+// wordCounts counts the lowercase words of s.
+func wordCounts(s string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range words(s) {
+		counts[word]++
+	}
+
+	return counts
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlight wraps the words of s found in terms in <mark> tags, like the
+// headline of PostRepo.Search.
+func highlight(s string, terms []string) string {
+	var (
+		b     strings.Builder
+		start = -1
+	)
+
+	flush := func(end int) {
+		word := s[start:end]
+		if contains(terms, strings.ToLower(word)) {
+			word = "<mark>" + word + "</mark>"
+		}
+		b.WriteString(word)
+		start = -1
+	}
+
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			flush(i)
+		}
+		if !isWord {
+			b.WriteRune(r)
+		}
+	}
+	if start >= 0 {
+		flush(len(s))
+	}
+
+	return b.String()
+}
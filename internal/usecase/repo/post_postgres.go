@@ -3,23 +3,43 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"fourth-exam/post-service-clean-arch/internal/entity"
 	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	_defaultSearchLanguage = "english"
+	_headlineOptions       = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
 )
 
 // PostRepo -.
 type PostRepo struct {
 	*postgres.Postgres
+
+	searchLanguage string
 }
 
 // New -.
-func New(pg *postgres.Postgres) *PostRepo {
-	return &PostRepo{pg}
+func New(pg *postgres.Postgres, opts ...Option) *PostRepo {
+	p := &PostRepo{
+		Postgres:       pg,
+		searchLanguage: _defaultSearchLanguage,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
 }
 
 // Create post -.
@@ -28,37 +48,69 @@ func (p *PostRepo) Create(ctx context.Context, req *entity.Post) (*entity.Post,
 		req.Id = uuid.New().String()
 	}
 
+	var publishAt interface{}
+	if req.PublishedAt != "" {
+		at, err := time.Parse(time.RFC3339Nano, req.PublishedAt)
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - CreatePost - time.Parse: %w", entity.ErrValidation)
+		}
+
+		// published_at has no time zone: store UTC, not the wall clock of at.
+		publishAt = at.UTC()
+	}
+
+	var apiKeyId interface{}
+	if req.APIKeyId != "" {
+		apiKeyId = req.APIKeyId
+	}
+
 	query, args, err := p.Builder.Insert("posts").
 		Columns(`
 			id,
 			user_id,
+			api_key_id,
 			content,
 			title,
-			likes,
-			dislikes,
-			views,
 			category,
+			status,
+			published_at,
+			search_language,
 			created_at
 		`).
 		Values(
-			req.Id, req.UserId, req.Content, req.Title,
-			req.Likes, req.Dislikes, req.Views, req.Category, time.Now()).Suffix(
-		`RETURNING created_at, updated_at`,
+			req.Id, req.UserId, apiKeyId, req.Content, req.Title,
+			req.Category, req.Status, publishAt, p.searchLanguage, time.Now()).Suffix(
+		`RETURNING likes, dislikes, views, comments_count, version, published_at, created_at, updated_at`,
 	).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("PostRepo - CreatePost - p.Builder: %w", err)
 	}
 	var (
-		createdAt time.Time
-		updatedAt sql.NullTime
+		publishedAt sql.NullTime
+		createdAt   time.Time
+		updatedAt   sql.NullTime
 	)
 
+	err = p.WithinTx(ctx, func(ctx context.Context) error {
+		row := p.Querier(ctx).QueryRow(ctx, query, args...)
+		if err := row.Scan(&req.Likes, &req.Dislikes, &req.Views, &req.CommentsCount, &req.Version, &publishedAt, &createdAt, &updatedAt); err != nil {
+			return fmt.Errorf("row.Scan: %w", translateError(err))
+		}
 
-	row := p.Pool.QueryRow(ctx, query, args...)
-	if err := row.Scan(&createdAt, &updatedAt); err != nil {
-		return nil, fmt.Errorf("PostRepo - CreatePost row.Scan: %v", err)
+		if err := p.setTags(ctx, req.Id, req.Tags); err != nil {
+			return fmt.Errorf("p.setTags: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - CreatePost - p.WithinTx: %w", err)
 	}
 
+	req.PublishedAt = ""
+	if publishedAt.Valid {
+		req.PublishedAt = publishedAt.Time.String()
+	}
 	req.CreatedAt = createdAt.String()
 	if updatedAt.Valid {
 		req.UpdatedAt = updatedAt.Time.String()
@@ -69,25 +121,12 @@ func (p *PostRepo) Create(ctx context.Context, req *entity.Post) (*entity.Post,
 
 // Get Post -.
 func (p *PostRepo) Get(ctx context.Context, id string) (*entity.Post, error) {
-	query := p.Builder.
-		Select(`
-		id,
-		user_id,
-		content,
-		title,
-		likes,
-		dislikes,
-		views,
-		category,
-		created_at,
-		updated_at
-		`).
-		From("posts")
+	query := p.selectPosts()
 
 	if id != "" {
-		query = query.Where(squirrel.Eq{"id": id})
+		query = query.Where(squirrel.Eq{"id": id, "deleted_at": nil})
 	} else {
-		return nil, fmt.Errorf("id is required")
+		return nil, fmt.Errorf("id is required: %w", entity.ErrValidation)
 	}
 
 	q, args, err := query.ToSql()
@@ -95,58 +134,69 @@ func (p *PostRepo) Get(ctx context.Context, id string) (*entity.Post, error) {
 		return nil, fmt.Errorf("PostRepo - GetPost - p.Builder: %w", err)
 	}
 
-	var (
-		post      entity.Post
-		createdAt time.Time
-		updatedAt sql.NullTime
-	)
-
-	row := p.Pool.QueryRow(ctx, q, args...)
-	if err := row.Scan(&post.Id, &post.UserId, &post.Content,
-		&post.Title, &post.Likes, &post.Dislikes, &post.Views, &post.Category, &createdAt, &updatedAt); err != nil {
-		return nil, fmt.Errorf("PostRepo - GetPost row.Scan: %w", err)
-	}
-
-	post.CreatedAt = createdAt.String()
-	if updatedAt.Valid {
-		post.UpdatedAt = updatedAt.Time.String()
+	post, err := scanPost(p.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - GetPost row.Scan: %w", translateError(err))
 	}
 
-	return &post, nil
-
+	return post, nil
 }
 
-// Update Post -.
+// Update Post overwrites the post if it is still at req.Version, saving the
+// replaced content as a revision -.
 func (p *PostRepo) Update(ctx context.Context, req *entity.Post) (*entity.Post, error) {
 	var (
 		updateMap = make(map[string]interface{})
-		where     = squirrel.Eq{"id": req.Id}
+		where     = squirrel.Eq{"id": req.Id, "version": req.Version, "deleted_at": nil}
 	)
 
 	updateMap["user_id"] = req.UserId
 	updateMap["content"] = req.Content
 	updateMap["title"] = req.Title
-	updateMap["likes"] = req.Likes
-	updateMap["dislikes"] = req.Dislikes
-	updateMap["views"] = req.Views
 	updateMap["category"] = req.Category
+	updateMap["version"] = squirrel.Expr("version + 1")
 	updateMap["updated_at"] = time.Now()
 
-	query := p.Builder.Update("posts").SetMap(updateMap).Where(where).Suffix("RETURNING created_at, updated_at")
+	query := p.Builder.Update("posts").SetMap(updateMap).Where(where).Suffix("RETURNING likes, dislikes, views, comments_count, version, status, published_at, created_at, updated_at")
 	var (
-		createdAt time.Time
-		updatedAt sql.NullTime
+		publishedAt sql.NullTime
+		createdAt   time.Time
+		updatedAt   sql.NullTime
 	)
 
 	q, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("PostRepo - UpdatePost - p.Builder: %w", err)
 	}
-	row := p.Pool.QueryRow(ctx, q, args...)
-	if err := row.Scan(&createdAt, &updatedAt); err != nil {
-		return nil, fmt.Errorf("PostRepo - GetPost row.Scan: %w", err)
+
+	err = p.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.snapshot(ctx, req.Id, req.Version); err != nil {
+			return fmt.Errorf("p.snapshot: %w", err)
+		}
+
+		row := p.Querier(ctx).QueryRow(ctx, q, args...)
+		if err := row.Scan(&req.Likes, &req.Dislikes, &req.Views, &req.CommentsCount, &req.Version,
+			&req.Status, &publishedAt, &createdAt, &updatedAt); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = p.staleOrMissing(ctx, req.Id)
+			}
+
+			return fmt.Errorf("row.Scan: %w", translateError(err))
+		}
+
+		if err := p.setTags(ctx, req.Id, req.Tags); err != nil {
+			return fmt.Errorf("p.setTags: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - UpdatePost - p.WithinTx: %w", err)
 	}
 
+	if publishedAt.Valid {
+		req.PublishedAt = publishedAt.Time.String()
+	}
 	req.CreatedAt = createdAt.String()
 	if updatedAt.Valid {
 		req.UpdatedAt = updatedAt.Time.String()
@@ -155,13 +205,77 @@ func (p *PostRepo) Update(ctx context.Context, req *entity.Post) (*entity.Post,
 	return req, nil
 }
 
-// Delete Post -,
-func (p *PostRepo) Delete(ctx context.Context, id string) error {
-	query := p.Builder.Delete("posts")
+// Patch updates only the fields set in patch, if the post is still at
+// patch.Version, saving the replaced content as a revision -.
+func (p *PostRepo) Patch(ctx context.Context, id string, patch *entity.PostPatch) (*entity.Post, error) {
+	updateMap := map[string]interface{}{
+		"version":    squirrel.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}
+
+	if patch.Title != nil {
+		updateMap["title"] = *patch.Title
+	}
+	if patch.Content != nil {
+		updateMap["content"] = *patch.Content
+	}
+	if patch.Category != nil {
+		updateMap["category"] = *patch.Category
+	}
+
+	q, args, err := p.Builder.Update("posts").
+		SetMap(updateMap).
+		Where(squirrel.Eq{"id": id, "version": patch.Version, "deleted_at": nil}).
+		Suffix("RETURNING " + _postColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - PatchPost - p.Builder: %w", err)
+	}
+
+	var post *entity.Post
+
+	err = p.WithinTx(ctx, func(ctx context.Context) error {
+		if err := p.snapshot(ctx, id, patch.Version); err != nil {
+			return fmt.Errorf("p.snapshot: %w", err)
+		}
+
+		var err error
+
+		post, err = scanPost(p.Querier(ctx).QueryRow(ctx, q, args...))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = p.staleOrMissing(ctx, id)
+			}
+
+			return fmt.Errorf("row.Scan: %w", translateError(err))
+		}
+
+		if patch.Tags != nil {
+			if err := p.setTags(ctx, id, *patch.Tags); err != nil {
+				return fmt.Errorf("p.setTags: %w", err)
+			}
+
+			post.Tags = *patch.Tags
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - PatchPost - p.WithinTx: %w", err)
+	}
+
+	return post, nil
+}
+
+// Delete Post soft-deletes the post by stamping deleted_at, if it is still at version -.
+func (p *PostRepo) Delete(ctx context.Context, id string, version int64) error {
+	query := p.Builder.Update("posts").
+		Set("deleted_at", squirrel.Expr("NOW()")).
+		Set("version", squirrel.Expr("version + 1"))
 	if id != "" {
-		query = query.Where(squirrel.Eq{"id": id})
+		query = query.Where(squirrel.Eq{"id": id, "version": version, "deleted_at": nil})
 	} else {
-		return fmt.Errorf("id is required")
+		return fmt.Errorf("id is required: %w", entity.ErrValidation)
 	}
 
 	q, args, err := query.ToSql()
@@ -169,72 +283,597 @@ func (p *PostRepo) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("PostRepo - DeletePost - p.Builder: %w", err)
 	}
 
-	_, err = p.Pool.Exec(ctx, q, args...)
+	tag, err := p.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("PostRepo - DeletePost row Exec: %w", translateError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("PostRepo - DeletePost: %w", translateError(p.staleOrMissing(ctx, id)))
+	}
+
+	return nil
+}
+
+// staleOrMissing explains why a versioned write matched no row: the post
+// exists at another version, or it doesn't exist at all.
+func (p *PostRepo) staleOrMissing(ctx context.Context, id string) error {
+	q, args, err := p.Builder.Select("1").From("posts").
+		Where(squirrel.Eq{"id": id, "deleted_at": nil}).ToSql()
+	if err != nil {
+		return fmt.Errorf("p.Builder: %w", err)
+	}
+
+	var exists int
+	if err := p.Querier(ctx).QueryRow(ctx, q, args...).Scan(&exists); err != nil {
+		return err
+	}
+
+	return entity.ErrVersionConflict
+}
+
+// Restore Post clears deleted_at of a soft-deleted post -.
+func (p *PostRepo) Restore(ctx context.Context, id string) error {
+	q, args, err := p.Builder.Update("posts").
+		Set("deleted_at", nil).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"id": id}).
+		Where(squirrel.NotEq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("PostRepo - RestorePost - p.Builder: %w", err)
+	}
+
+	tag, err := p.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("PostRepo - RestorePost row Exec: %w", translateError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("PostRepo - RestorePost: %w", translateError(pgx.ErrNoRows))
+	}
+
+	return nil
+}
+
+// Transition moves the post to change.To, if it is still in change.From -.
+func (p *PostRepo) Transition(ctx context.Context, id string, change *entity.StatusChange) (*entity.Post, error) {
+	query := p.Builder.Update("posts").
+		Set("status", change.To).
+		Set("version", squirrel.Expr("version + 1")).
+		Set("updated_at", time.Now())
+
+	switch {
+	case change.PublishedAt != nil:
+		query = query.Set("published_at", change.PublishedAt.UTC())
+	case change.ClearPublishedAt:
+		query = query.Set("published_at", nil)
+	}
+
+	q, args, err := query.
+		Where(squirrel.Eq{"id": id, "status": change.From, "deleted_at": nil}).
+		Suffix("RETURNING " + _postColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - TransitionPost - p.Builder: %w", err)
+	}
+
+	post, err := scanPost(p.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = p.staleOrMissing(ctx, id)
+		}
+
+		return nil, fmt.Errorf("PostRepo - TransitionPost row.Scan: %w", translateError(err))
+	}
+
+	return post, nil
+}
+
+// PublishDue publishes the scheduled posts due at now -.
+func (p *PostRepo) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	q, args, err := p.Builder.Update("posts").
+		Set("status", entity.StatusPublished).
+		Set("version", squirrel.Expr("version + 1")).
+		Where(squirrel.Eq{"status": entity.StatusScheduled, "deleted_at": nil}).
+		Where(squirrel.LtOrEq{"published_at": now.UTC()}).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - PublishDuePosts - p.Builder: %w", err)
+	}
+
+	tag, err := p.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - PublishDuePosts row Exec: %w", translateError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Reindex moves the posts whose search vector was built in another language
+// to the search language of the repository, after it was changed, so the
+// vectors match the queries. It returns how many posts it re-indexed.
+func (p *PostRepo) Reindex(ctx context.Context) (int64, error) {
+	q, args, err := p.Builder.Update("posts").
+		Set("search_language", p.searchLanguage).
+		Where("search_language <> ?::regconfig", p.searchLanguage).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - Reindex - p.Builder: %w", err)
+	}
+
+	tag, err := p.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - Reindex - Exec: %w", translateError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Purge hard-deletes posts soft-deleted more than retention ago -.
+func (p *PostRepo) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	q, args, err := p.Builder.Delete("posts").
+		Where(squirrel.Expr("deleted_at < NOW() - ? * INTERVAL '1 second'", int64(retention.Seconds()))).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - PurgePosts - p.Builder: %w", err)
+	}
+
+	tag, err := p.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - PurgePosts row Exec: %w", translateError(err))
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// React sets the user's reaction on a post, keeping the likes/dislikes counters in sync -.
+func (p *PostRepo) React(ctx context.Context, req *entity.Reaction) error {
+	return p.setReaction(ctx, req.PostId, req.UserId, req.Reaction)
+}
+
+// Unreact removes the user's reaction from a post -.
+func (p *PostRepo) Unreact(ctx context.Context, postId, userId string) error {
+	return p.setReaction(ctx, postId, userId, "")
+}
+
+// setReaction switches the user between like, dislike and no reaction ("").
+// The post row is locked first so concurrent reactions on the same post are
+// serialized and the counters always match the post_reactions rows.
+func (p *PostRepo) setReaction(ctx context.Context, postId, userId, reaction string) error {
+	err := p.WithinTx(ctx, func(ctx context.Context) error {
+		q, args, err := p.Builder.Select("id").From("posts").
+			Where(squirrel.Eq{"id": postId, "deleted_at": nil}).Suffix("FOR UPDATE").ToSql()
+		if err != nil {
+			return fmt.Errorf("p.Builder: %w", err)
+		}
+		if err := p.Querier(ctx).QueryRow(ctx, q, args...).Scan(&postId); err != nil {
+			return fmt.Errorf("lock post row.Scan: %w", translateError(err))
+		}
+
+		q, args, err = p.Builder.Select("reaction").From("post_reactions").
+			Where(squirrel.Eq{"post_id": postId, "user_id": userId}).ToSql()
+		if err != nil {
+			return fmt.Errorf("p.Builder: %w", err)
+		}
+
+		var prev string
+		if err := p.Querier(ctx).QueryRow(ctx, q, args...).Scan(&prev); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("previous reaction row.Scan: %w", translateError(err))
+		}
+
+		if prev == reaction {
+			return nil
+		}
+
+		if reaction == "" {
+			q, args, err = p.Builder.Delete("post_reactions").
+				Where(squirrel.Eq{"post_id": postId, "user_id": userId}).ToSql()
+		} else {
+			q, args, err = p.Builder.Insert("post_reactions").
+				Columns("post_id", "user_id", "reaction").
+				Values(postId, userId, reaction).
+				Suffix("ON CONFLICT (post_id, user_id) DO UPDATE SET reaction = EXCLUDED.reaction, updated_at = NOW()").
+				ToSql()
+		}
+		if err != nil {
+			return fmt.Errorf("p.Builder: %w", err)
+		}
+		if _, err := p.Querier(ctx).Exec(ctx, q, args...); err != nil {
+			return fmt.Errorf("reaction Exec: %w", translateError(err))
+		}
+
+		prevLikes, prevDislikes := reactionCounts(prev)
+		likes, dislikes := reactionCounts(reaction)
+
+		q, args, err = p.Builder.Update("posts").
+			Set("likes", squirrel.Expr("likes + ?", likes-prevLikes)).
+			Set("dislikes", squirrel.Expr("dislikes + ?", dislikes-prevDislikes)).
+			Where(squirrel.Eq{"id": postId}).ToSql()
+		if err != nil {
+			return fmt.Errorf("p.Builder: %w", err)
+		}
+		if _, err := p.Querier(ctx).Exec(ctx, q, args...); err != nil {
+			return fmt.Errorf("counters Exec: %w", translateError(err))
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("PostRepo - DeletePost row Exec: %w", err)
+		return fmt.Errorf("PostRepo - setReaction - p.WithinTx: %w", err)
 	}
 
 	return nil
 }
 
+// reactionCounts returns how a reaction contributes to the likes and dislikes counters.
+func reactionCounts(reaction string) (likes, dislikes int64) {
+	switch reaction {
+	case entity.ReactionLike:
+		return 1, 0
+	case entity.ReactionDislike:
+		return 0, 1
+	default:
+		return 0, 0
+	}
+}
+
 // List Posts -.
 func (p *PostRepo) List(ctx context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
-	query := p.Builder.
-		Select(`
-		id,
-		user_id,
-		content,
-		title,
-		likes,
-		dislikes,
-		views,
-		category,
-		created_at,
-		updated_at
-		`).
-		From("posts")
+	query := p.selectPosts().Where(listConditions(req))
 
-	query = query.Offset(uint64((req.Page - 1) * req.Limit)).Limit(uint64(req.Limit))
-	query = query.OrderBy(req.OrderBy)
-
-	if req.UserId != "" {
-		query = query.Where(squirrel.Eq{"user_id": req.UserId})
+	orderBy, err := orderByClauses(req.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListPost - orderByClauses: %w", err)
 	}
 
+	query = query.Offset(uint64((req.Page - 1) * req.Limit)).Limit(uint64(req.Limit) + 1)
+	query = query.OrderBy(orderBy...)
+
 	q, args, err := query.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("PostRepo - ListPost - p.Builder: %w", err)
 	}
 
-	rows, err := p.Pool.Query(ctx, q, args...)
+	rows, err := p.Querier(ctx).Query(ctx, q, args...)
 	if err != nil {
-		return nil, fmt.Errorf("PostRepo - PostHistory - r.Pool.Query: %w", err)
+		return nil, fmt.Errorf("PostRepo - PostHistory - p.Querier.Query: %w", translateError(err))
 	}
 	defer rows.Close()
 
 	var (
-		posts = entity.Posts{Count: 0}
+		posts = entity.Posts{Count: 0, Page: req.Page, Limit: req.Limit}
 	)
 
 	for rows.Next() {
-		var (
-			createdAt time.Time
-			updatedAt sql.NullTime
-			post      entity.Post
-		)
-		if err := rows.Scan(&post.Id, &post.UserId, &post.Content,
-			&post.Title, &post.Likes, &post.Dislikes, &post.Views, &post.Category, &createdAt, &updatedAt); err != nil {
-			return nil, fmt.Errorf("PostRepo - ListPost row.Scan: %w", err)
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - ListPost row.Scan: %w", translateError(err))
 		}
 
-		post.CreatedAt = createdAt.String()
-		if updatedAt.Valid {
-			post.UpdatedAt = updatedAt.Time.String()
+		posts.Count++
+		posts.Items = append(posts.Items, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PostRepo - ListPost rows.Err: %w", translateError(err))
+	}
+
+	if posts.Count > req.Limit {
+		posts.HasMore = true
+		posts.Count = req.Limit
+		posts.Items = posts.Items[:req.Limit]
+	}
+
+	if req.WithTotal {
+		total, err := p.count(ctx, req)
+		if err != nil {
+			return nil, err
 		}
 
-		posts.Count++
-		posts.Items = append(posts.Items, &post)
+		posts.Total = &total
+	}
+
+	return &posts, nil
+}
+
+// Search returns the posts matching a full-text query, most relevant first
+// unless req.Sort says otherwise -.
+func (p *PostRepo) Search(ctx context.Context, req *entity.SearchFilter) (*entity.SearchResults, error) {
+	lang := p.searchLanguage
+
+	orderBy := []string{"rank DESC", "id ASC"}
+	if len(req.Sort) > 0 {
+		var err error
+
+		orderBy, err = orderByClauses(req.Sort)
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - SearchPosts - orderByClauses: %w", err)
+		}
+	}
+
+	q, args, err := p.selectPosts().
+		Column("ts_rank_cd(search_vector, query) AS rank").
+		Column("ts_headline(?::regconfig, content, query, ?) AS headline", lang, _headlineOptions).
+		CrossJoin("websearch_to_tsquery(?::regconfig, ?) AS query", lang, req.Query).
+		Where("search_vector @@ query").
+		Where(listConditions(&req.GetListFilter)).
+		OrderBy(orderBy...).
+		Offset(uint64((req.Page - 1) * req.Limit)).
+		Limit(uint64(req.Limit) + 1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - SearchPosts - p.Builder: %w", err)
+	}
+
+	rows, err := p.Querier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - SearchPosts - p.Querier.Query: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	results := entity.SearchResults{Page: req.Page, Limit: req.Limit}
+
+	for rows.Next() {
+		var result entity.SearchResult
+
+		result.Post, err = scanPost(rows, &result.Rank, &result.Headline)
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - SearchPosts row.Scan: %w", translateError(err))
+		}
+
+		results.Items = append(results.Items, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PostRepo - SearchPosts rows.Err: %w", translateError(err))
+	}
+
+	if int64(len(results.Items)) > req.Limit {
+		results.HasMore = true
+		results.Items = results.Items[:req.Limit]
+	}
+	results.Count = int64(len(results.Items))
+
+	if req.WithTotal {
+		q, args, err := p.Builder.Select("COUNT(*)").From("posts").
+			CrossJoin("websearch_to_tsquery(?::regconfig, ?) AS query", lang, req.Query).
+			Where("search_vector @@ query").
+			Where(listConditions(&req.GetListFilter)).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - SearchPosts - p.Builder: %w", err)
+		}
+
+		var total int64
+		if err := p.Querier(ctx).QueryRow(ctx, q, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("PostRepo - SearchPosts count row.Scan: %w", translateError(err))
+		}
+
+		results.Total = &total
+	}
+
+	return &results, nil
+}
+
+// count returns the number of posts matching the filter, ignoring pagination.
+func (p *PostRepo) count(ctx context.Context, req *entity.GetListFilter) (int64, error) {
+	q, args, err := p.Builder.Select("COUNT(*)").From("posts").Where(listConditions(req)).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("PostRepo - CountPosts - p.Builder: %w", err)
+	}
+
+	var total int64
+	if err := p.Querier(ctx).QueryRow(ctx, q, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("PostRepo - CountPosts row.Scan: %w", translateError(err))
+	}
+
+	return total, nil
+}
+
+// ListByCursor returns the page of posts after (or, with req.Backward, before)
+// req.Keyset in created_at DESC, id DESC order -.
+func (p *PostRepo) ListByCursor(ctx context.Context, req *entity.GetListFilter) (*entity.Posts, error) {
+	query := p.selectPosts().Limit(uint64(req.Limit) + 1)
+
+	if req.Backward {
+		query = query.OrderBy("created_at ASC", "id ASC")
+	} else {
+		query = query.OrderBy("created_at DESC", "id DESC")
+	}
+
+	if req.Keyset != nil {
+		cmp := "<"
+		if req.Backward {
+			cmp = ">"
+		}
+		query = query.Where("(created_at, id) "+cmp+" (?, ?)", req.Keyset.CreatedAt, req.Keyset.Id)
+	}
+	query = query.Where(listConditions(req))
+
+	q, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListByCursor - p.Builder: %w", err)
 	}
 
+	rows, err := p.Querier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListByCursor - p.Querier.Query: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	posts := entity.Posts{Limit: req.Limit}
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - ListByCursor row.Scan: %w", translateError(err))
+		}
+
+		posts.Items = append(posts.Items, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PostRepo - ListByCursor rows.Err: %w", translateError(err))
+	}
+
+	if int64(len(posts.Items)) > req.Limit {
+		posts.HasMore = true
+		posts.Items = posts.Items[:req.Limit]
+	}
+
+	if req.Backward {
+		for i, j := 0, len(posts.Items)-1; i < j; i, j = i+1, j-1 {
+			posts.Items[i], posts.Items[j] = posts.Items[j], posts.Items[i]
+		}
+	}
+
+	posts.Count = int64(len(posts.Items))
+
 	return &posts, nil
 }
+
+// sortColumns maps the sortable fields to their posts columns. Only these
+// ever reach the ORDER BY clause.
+var sortColumns = map[string]string{
+	"likes":      "likes",
+	"views":      "views",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"title":      "title",
+}
+
+// orderByClauses turns a sort spec into ORDER BY clauses, defaulting to
+// created_at and always ending with id so pages are stable.
+func orderByClauses(sort []entity.SortField) ([]string, error) {
+	if len(sort) == 0 {
+		sort = []entity.SortField{{Field: "created_at"}}
+	}
+
+	clauses := make([]string, 0, len(sort)+1)
+	for _, f := range sort {
+		column, ok := sortColumns[f.Field]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort field %q: %w", f.Field, entity.ErrValidation)
+		}
+
+		if f.Desc {
+			clauses = append(clauses, column+" DESC")
+		} else {
+			clauses = append(clauses, column+" ASC")
+		}
+	}
+
+	return append(clauses, "id ASC"), nil
+}
+
+// listConditions returns the WHERE clause shared by the list queries.
+func listConditions(req *entity.GetListFilter) squirrel.And {
+	conds := squirrel.And{}
+
+	if req.UserId != "" {
+		conds = append(conds, squirrel.Eq{"user_id": req.UserId})
+	}
+	if len(req.Categories) > 0 {
+		conds = append(conds, squirrel.Eq{"category": req.Categories})
+	}
+	if len(req.Ids) > 0 {
+		conds = append(conds, squirrel.Eq{"id": req.Ids})
+	}
+	if req.TitlePrefix != "" {
+		conds = append(conds, squirrel.ILike{"title": escapeLike(req.TitlePrefix) + "%"})
+	}
+	if req.CreatedFrom != nil {
+		conds = append(conds, squirrel.GtOrEq{"created_at": *req.CreatedFrom})
+	}
+	if req.CreatedTo != nil {
+		conds = append(conds, squirrel.Lt{"created_at": *req.CreatedTo})
+	}
+	if req.UpdatedFrom != nil {
+		conds = append(conds, squirrel.GtOrEq{"updated_at": *req.UpdatedFrom})
+	}
+	if req.UpdatedTo != nil {
+		conds = append(conds, squirrel.Lt{"updated_at": *req.UpdatedTo})
+	}
+	if req.MinLikes != nil {
+		conds = append(conds, squirrel.GtOrEq{"likes": *req.MinLikes})
+	}
+	if req.MinViews != nil {
+		conds = append(conds, squirrel.GtOrEq{"views": *req.MinViews})
+	}
+	if len(req.Statuses) > 0 {
+		conds = append(conds, squirrel.Eq{"status": req.Statuses})
+	}
+	if len(req.TagsAny) > 0 {
+		conds = append(conds, squirrel.Expr("ARRAY("+_postTagNames+") && ?::text[]", req.TagsAny))
+	}
+	if len(req.TagsAll) > 0 {
+		conds = append(conds, squirrel.Expr("ARRAY("+_postTagNames+") @> ?::text[]", req.TagsAll))
+	}
+	if !req.IncludeDeleted {
+		conds = append(conds, squirrel.Eq{"deleted_at": nil})
+	}
+
+	return conds
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// _postColumns are the columns scanned by scanPost.
+const _postColumns = `
+		id,
+		user_id,
+		api_key_id,
+		content,
+		title,
+		likes,
+		dislikes,
+		views,
+		category,
+		comments_count,
+		version,
+		created_at,
+		updated_at,
+		deleted_at,
+		status,
+		published_at,
+		ARRAY(` + _postTagNames + ` ORDER BY t.name) AS tags
+		`
+
+// selectPosts starts a query returning the columns scanned by scanPost.
+func (p *PostRepo) selectPosts() squirrel.SelectBuilder {
+	return p.Builder.Select(_postColumns).From("posts")
+}
+
+// scanPost scans the columns of selectPosts followed by any extra columns.
+func scanPost(row pgx.Row, extra ...interface{}) (*entity.Post, error) {
+	var (
+		post        entity.Post
+		createdAt   time.Time
+		updatedAt   sql.NullTime
+		deletedAt   sql.NullTime
+		publishedAt sql.NullTime
+		apiKeyId    sql.NullString
+	)
+
+	dest := append([]interface{}{&post.Id, &post.UserId, &apiKeyId, &post.Content,
+		&post.Title, &post.Likes, &post.Dislikes, &post.Views, &post.Category,
+		&post.CommentsCount, &post.Version, &createdAt, &updatedAt, &deletedAt,
+		&post.Status, &publishedAt, &post.Tags}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	post.APIKeyId = apiKeyId.String
+	post.CreatedAt = createdAt.String()
+	if updatedAt.Valid {
+		post.UpdatedAt = updatedAt.Time.String()
+	}
+	if deletedAt.Valid {
+		post.DeletedAt = deletedAt.Time.String()
+	}
+	if publishedAt.Valid {
+		post.PublishedAt = publishedAt.Time.String()
+	}
+
+	return &post, nil
+}
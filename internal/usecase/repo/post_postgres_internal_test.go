@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderByClauses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		sort []entity.SortField
+		want []string
+		err  bool
+	}{
+		{"default", nil, []string{"created_at ASC", "id ASC"}, false},
+		{"ascending", []entity.SortField{{Field: "title"}}, []string{"title ASC", "id ASC"}, false},
+		{"descending", []entity.SortField{{Field: "likes", Desc: true}}, []string{"likes DESC", "id ASC"}, false},
+		{
+			"several fields",
+			[]entity.SortField{{Field: "views", Desc: true}, {Field: "updated_at"}, {Field: "created_at", Desc: true}},
+			[]string{"views DESC", "updated_at ASC", "created_at DESC", "id ASC"},
+			false,
+		},
+		{"unknown field", []entity.SortField{{Field: "likes"}, {Field: "id; DROP TABLE posts"}}, nil, true},
+		{"id isn't sortable", []entity.SortField{{Field: "id"}}, nil, true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := orderByClauses(tc.sort)
+			if tc.err {
+				require.ErrorIs(t, err, entity.ErrValidation)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSortColumns(t *testing.T) {
+	t.Parallel()
+
+	require.Len(t, sortColumns, len(entity.SortableFields))
+
+	for _, field := range entity.SortableFields {
+		require.Contains(t, sortColumns, field, "every sortable field has a column")
+	}
+}
@@ -0,0 +1,711 @@
+// Package repotest is the contract every PostRepo, CommentRepo and
+// APIKeyRepo implementation must honour, run by the tests of each implementation.
+package repotest
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Env is the repositories a contract test runs against.
+type Env struct {
+	Posts    usecase.PostRepo
+	Comments usecase.CommentRepo
+	APIKeys  usecase.APIKeyRepo
+
+	// UserId is a user the repositories accept as an author.
+	UserId string
+}
+
+// Test is one contract test, calling the repositories of env with ctx.
+type Test func(ctx context.Context, t *testing.T, env Env)
+
+// Run runs test against repositories that no other test changes. They may
+// hold other posts, so tests only look at the posts they create.
+type Run func(t *testing.T, test Test)
+
+// PostRepo runs the PostRepo contract.
+func PostRepo(t *testing.T, run Run) {
+	t.Run("create and get", func(t *testing.T) { run(t, testCreateAndGet) })
+	t.Run("update checks the version and saves a revision", func(t *testing.T) { run(t, testUpdate) })
+	t.Run("patch changes only the given fields", func(t *testing.T) { run(t, testPatch) })
+	t.Run("delete and restore", func(t *testing.T) { run(t, testDeleteAndRestore) })
+	t.Run("status transitions", func(t *testing.T) { run(t, testTransition) })
+	t.Run("reactions", func(t *testing.T) { run(t, testReactions) })
+	t.Run("views", func(t *testing.T) { run(t, testViews) })
+	t.Run("list filters, sorts and paginates", func(t *testing.T) { run(t, testList) })
+	t.Run("list by cursor", func(t *testing.T) { run(t, testListByCursor) })
+	t.Run("search", func(t *testing.T) { run(t, testSearch) })
+	t.Run("tags", func(t *testing.T) { run(t, testTags) })
+	t.Run("revisions", func(t *testing.T) { run(t, testRevisions) })
+	t.Run("purge", func(t *testing.T) { run(t, testPurge) })
+}
+
+// CommentRepo runs the CommentRepo contract.
+func CommentRepo(t *testing.T, run Run) {
+	t.Run("threads and comments_count", func(t *testing.T) { run(t, testComments) })
+}
+
+// APIKeyRepo runs the APIKeyRepo contract.
+func APIKeyRepo(t *testing.T, run Run) {
+	t.Run("mint, use and revoke", func(t *testing.T) { run(t, testAPIKeys) })
+	t.Run("posts and comments keep the key that wrote them", func(t *testing.T) { run(t, testAPIKeyWrites) })
+}
+
+// unique returns a lowercase name no other test run uses, to tell the posts
+// of a test apart.
+func unique(prefix string) string {
+	return prefix + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+}
+
+// create creates a published post in category, changed by mods first.
+func create(ctx context.Context, t *testing.T, env Env, category string, mods ...func(*entity.Post)) *entity.Post {
+	t.Helper()
+
+	post := &entity.Post{
+		UserId:   env.UserId,
+		Title:    "Title",
+		Content:  "Content",
+		Category: category,
+		Status:   entity.StatusPublished,
+		Tags:     []string{},
+	}
+	for _, mod := range mods {
+		mod(post)
+	}
+	post.Tags = entity.NormalizeTags(post.Tags)
+
+	created, err := env.Posts.Create(ctx, post)
+	require.NoError(t, err)
+
+	return created
+}
+
+func get(ctx context.Context, t *testing.T, env Env, id string) *entity.Post {
+	t.Helper()
+
+	post, err := env.Posts.Get(ctx, id)
+	require.NoError(t, err)
+
+	return post
+}
+
+// instant parses a timestamp field of a post.
+func instant(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	at, err := time.Parse(entity.TimestampLayout, s)
+	require.NoError(t, err)
+
+	return at
+}
+
+func ids(posts []*entity.Post) []string {
+	ids := make([]string, len(posts))
+	for i, p := range posts {
+		ids[i] = p.Id
+	}
+
+	return ids
+}
+
+func testCreateAndGet(ctx context.Context, t *testing.T, env Env) {
+	created := create(ctx, t, env, unique("c"), func(p *entity.Post) {
+		p.Title, p.Content, p.Tags = "Hello", "World", []string{"b", "a"}
+	})
+
+	require.NotEmpty(t, created.Id)
+	require.Equal(t, int64(1), created.Version)
+	require.NotEmpty(t, created.CreatedAt)
+	require.Empty(t, created.UpdatedAt)
+
+	got := get(ctx, t, env, created.Id)
+	require.Equal(t, "Hello", got.Title)
+	require.Equal(t, "World", got.Content)
+	require.Equal(t, created.Category, got.Category)
+	require.Equal(t, env.UserId, got.UserId)
+	require.Equal(t, entity.StatusPublished, got.Status)
+	require.Equal(t, []string{"a", "b"}, got.Tags)
+	require.Equal(t, created.CreatedAt, got.CreatedAt)
+	require.Equal(t, int64(1), got.Version)
+	require.Zero(t, got.Likes+got.Dislikes+got.Views+got.CommentsCount)
+
+	_, err := got.Keyset()
+	require.NoError(t, err, "created_at must parse as entity.TimestampLayout")
+
+	_, err = env.Posts.Create(ctx, &entity.Post{Id: created.Id, UserId: env.UserId, Category: created.Category, Status: entity.StatusDraft})
+	require.ErrorIs(t, err, entity.ErrConflict)
+
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	scheduled := create(ctx, t, env, created.Category, func(p *entity.Post) {
+		p.Status, p.PublishedAt = entity.StatusScheduled, at.In(time.FixedZone("UTC-5", -5*60*60)).Format(time.RFC3339)
+	})
+	require.True(t, at.Equal(instant(t, get(ctx, t, env, scheduled.Id).PublishedAt)), "published_at keeps the instant, not the wall clock")
+
+	_, err = env.Posts.Get(ctx, uuid.NewString())
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	_, err = env.Posts.Get(ctx, "not-a-uuid")
+	require.ErrorIs(t, err, entity.ErrValidation)
+}
+
+func testUpdate(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"), func(p *entity.Post) {
+		p.Title, p.Tags = "Old", []string{"old"}
+	})
+
+	edit := *post
+	edit.Title, edit.Tags, edit.Version = "New", []string{"new"}, 2
+
+	_, err := env.Posts.Update(ctx, &edit)
+	require.ErrorIs(t, err, entity.ErrVersionConflict)
+
+	missing := edit
+	missing.Id, missing.Version = uuid.NewString(), 1
+
+	_, err = env.Posts.Update(ctx, &missing)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	edit.Version = 1
+
+	updated, err := env.Posts.Update(ctx, &edit)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.Version)
+	require.NotEmpty(t, updated.UpdatedAt)
+
+	got := get(ctx, t, env, post.Id)
+	require.Equal(t, "New", got.Title)
+	require.Equal(t, []string{"new"}, got.Tags)
+	require.Equal(t, int64(2), got.Version)
+
+	revision, err := env.Posts.GetRevision(ctx, post.Id, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Old", revision.Title)
+	require.Equal(t, []string{"old"}, revision.Tags)
+	require.Equal(t, post.CreatedAt, revision.EditedAt)
+
+	_, err = env.Posts.GetRevision(ctx, post.Id, 2)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func testPatch(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"), func(p *entity.Post) {
+		p.Title, p.Content, p.Tags = "Title", "Content", []string{"kept"}
+	})
+
+	title := "Patched"
+
+	patched, err := env.Posts.Patch(ctx, post.Id, &entity.PostPatch{Title: &title, Version: 1})
+	require.NoError(t, err)
+	require.Equal(t, "Patched", patched.Title)
+	require.Equal(t, "Content", patched.Content)
+	require.Equal(t, []string{"kept"}, patched.Tags)
+	require.Equal(t, int64(2), patched.Version)
+
+	tags := []string{"replaced"}
+
+	patched, err = env.Posts.Patch(ctx, post.Id, &entity.PostPatch{Tags: &tags, Version: 2})
+	require.NoError(t, err)
+	require.Equal(t, []string{"replaced"}, patched.Tags)
+	require.Equal(t, []string{"replaced"}, get(ctx, t, env, post.Id).Tags)
+
+	_, err = env.Posts.Patch(ctx, post.Id, &entity.PostPatch{Title: &title, Version: 2})
+	require.ErrorIs(t, err, entity.ErrVersionConflict)
+}
+
+func testDeleteAndRestore(ctx context.Context, t *testing.T, env Env) {
+	category := unique("c")
+	post := create(ctx, t, env, category)
+
+	require.ErrorIs(t, env.Posts.Delete(ctx, post.Id, 2), entity.ErrVersionConflict)
+	require.NoError(t, env.Posts.Delete(ctx, post.Id, 1))
+	require.ErrorIs(t, env.Posts.Delete(ctx, post.Id, 2), entity.ErrNotFound)
+
+	_, err := env.Posts.Get(ctx, post.Id)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	filter := &entity.GetListFilter{Categories: []string{category}, Page: 1, Limit: 10}
+
+	posts, err := env.Posts.List(ctx, filter)
+	require.NoError(t, err)
+	require.Empty(t, posts.Items)
+
+	filter.IncludeDeleted = true
+
+	posts, err = env.Posts.List(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, posts.Items, 1)
+	require.NotEmpty(t, posts.Items[0].DeletedAt)
+
+	require.NoError(t, env.Posts.Restore(ctx, post.Id))
+	require.ErrorIs(t, env.Posts.Restore(ctx, post.Id), entity.ErrNotFound)
+
+	got := get(ctx, t, env, post.Id)
+	require.Equal(t, int64(3), got.Version)
+	require.Empty(t, got.DeletedAt)
+}
+
+func testTransition(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"), func(p *entity.Post) { p.Status = entity.StatusDraft })
+
+	_, err := env.Posts.Transition(ctx, post.Id, &entity.StatusChange{From: entity.StatusPublished, To: entity.StatusDraft})
+	require.ErrorIs(t, err, entity.ErrVersionConflict)
+
+	_, err = env.Posts.Transition(ctx, uuid.NewString(), &entity.StatusChange{From: entity.StatusDraft, To: entity.StatusArchived})
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	// A time in another zone is the same instant once stored.
+	due := time.Now().Add(-time.Minute).Truncate(time.Microsecond).In(time.FixedZone("UTC+3", 3*60*60))
+
+	scheduled, err := env.Posts.Transition(ctx, post.Id, &entity.StatusChange{
+		From: entity.StatusDraft, To: entity.StatusScheduled, PublishedAt: &due,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusScheduled, scheduled.Status)
+	require.True(t, due.Equal(instant(t, scheduled.PublishedAt)), scheduled.PublishedAt)
+	require.Equal(t, int64(2), scheduled.Version)
+
+	published, err := env.Posts.PublishDue(ctx, time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, published, int64(1))
+
+	got := get(ctx, t, env, post.Id)
+	require.Equal(t, entity.StatusPublished, got.Status)
+	require.Equal(t, int64(3), got.Version)
+
+	draft, err := env.Posts.Transition(ctx, post.Id, &entity.StatusChange{
+		From: entity.StatusPublished, To: entity.StatusDraft, ClearPublishedAt: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusDraft, draft.Status)
+	require.Empty(t, draft.PublishedAt)
+}
+
+func testReactions(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"))
+
+	react := func(reaction string) *entity.Post {
+		t.Helper()
+
+		require.NoError(t, env.Posts.React(ctx, &entity.Reaction{PostId: post.Id, UserId: env.UserId, Reaction: reaction}))
+
+		return get(ctx, t, env, post.Id)
+	}
+
+	got := react(entity.ReactionLike)
+	require.Equal(t, [2]int64{1, 0}, [2]int64{got.Likes, got.Dislikes})
+
+	got = react(entity.ReactionLike)
+	require.Equal(t, [2]int64{1, 0}, [2]int64{got.Likes, got.Dislikes}, "reacting twice counts once")
+
+	got = react(entity.ReactionDislike)
+	require.Equal(t, [2]int64{0, 1}, [2]int64{got.Likes, got.Dislikes})
+
+	require.NoError(t, env.Posts.Unreact(ctx, post.Id, env.UserId))
+	require.NoError(t, env.Posts.Unreact(ctx, post.Id, env.UserId))
+
+	got = get(ctx, t, env, post.Id)
+	require.Equal(t, [2]int64{0, 0}, [2]int64{got.Likes, got.Dislikes})
+	require.Equal(t, int64(1), got.Version, "reactions don't change the version")
+
+	err := env.Posts.React(ctx, &entity.Reaction{PostId: uuid.NewString(), UserId: env.UserId, Reaction: entity.ReactionLike})
+	require.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func testViews(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"))
+
+	require.NoError(t, env.Posts.AddViews(ctx, map[string]int64{post.Id: 3, uuid.NewString(): 1}))
+	require.NoError(t, env.Posts.AddViews(ctx, map[string]int64{post.Id: 2}))
+
+	got := get(ctx, t, env, post.Id)
+	require.Equal(t, int64(5), got.Views)
+	require.Equal(t, int64(1), got.Version, "views don't change the version")
+
+	require.NoError(t, env.Posts.Delete(ctx, post.Id, 1))
+	require.NoError(t, env.Posts.AddViews(ctx, map[string]int64{post.Id: 4}))
+	require.NoError(t, env.Posts.Restore(ctx, post.Id))
+	require.Equal(t, int64(5), get(ctx, t, env, post.Id).Views, "deleted posts aren't viewed")
+}
+
+func testList(ctx context.Context, t *testing.T, env Env) {
+	category := unique("c")
+
+	posts := []*entity.Post{
+		create(ctx, t, env, category, func(p *entity.Post) { p.Title, p.Tags = "Alpha", []string{"go", "sql"} }),
+		create(ctx, t, env, category, func(p *entity.Post) { p.Title, p.Tags = "alpine", []string{"go"} }),
+		create(ctx, t, env, category, func(p *entity.Post) { p.Title, p.Tags = "Beta", []string{"sql"} }),
+		create(ctx, t, env, category, func(p *entity.Post) { p.Title = "Gamma" }),
+		create(ctx, t, env, category, func(p *entity.Post) { p.Title, p.Status = "Delta", entity.StatusDraft }),
+	}
+
+	views := []int64{5, 3, 3, 1, 0}
+	for i, post := range posts {
+		require.NoError(t, env.Posts.AddViews(ctx, map[string]int64{post.Id: views[i]}))
+	}
+
+	// posts[1] and posts[2] tie on views and are ordered by id.
+	tied := ids(posts[1:3])
+	sort.Strings(tied)
+	byViews := append(append([]string{posts[0].Id}, tied...), posts[3].Id, posts[4].Id)
+
+	list := func(filter entity.GetListFilter) *entity.Posts {
+		t.Helper()
+
+		filter.Categories = []string{category}
+		if filter.Page == 0 {
+			filter.Page, filter.Limit = 1, 10
+		}
+
+		res, err := env.Posts.List(ctx, &filter)
+		require.NoError(t, err)
+
+		return res
+	}
+
+	sortByViews := []entity.SortField{{Field: "views", Desc: true}}
+
+	page := list(entity.GetListFilter{Page: 1, Limit: 2, Sort: sortByViews, WithTotal: true})
+	require.Equal(t, byViews[:2], ids(page.Items))
+	require.Equal(t, int64(2), page.Count)
+	require.True(t, page.HasMore)
+	require.NotNil(t, page.Total)
+	require.Equal(t, int64(5), *page.Total)
+
+	page = list(entity.GetListFilter{Page: 3, Limit: 2, Sort: sortByViews})
+	require.Equal(t, byViews[4:], ids(page.Items))
+	require.False(t, page.HasMore)
+	require.Nil(t, page.Total)
+
+	page = list(entity.GetListFilter{Sort: []entity.SortField{{Field: "views"}}})
+	require.Equal(t, []string{byViews[4], byViews[3], byViews[1], byViews[2], byViews[0]}, ids(page.Items))
+
+	require.ElementsMatch(t, ids(posts[:2]), ids(list(entity.GetListFilter{TitlePrefix: "AL"}).Items))
+
+	minViews := int64(3)
+	require.ElementsMatch(t, ids(posts[:3]), ids(list(entity.GetListFilter{MinViews: &minViews}).Items))
+
+	require.ElementsMatch(t, ids(posts[:3]), ids(list(entity.GetListFilter{TagsAny: []string{"go", "sql"}}).Items))
+	require.ElementsMatch(t, ids(posts[:1]), ids(list(entity.GetListFilter{TagsAll: []string{"go", "sql"}}).Items))
+
+	require.ElementsMatch(t, ids(posts[3:]), ids(list(entity.GetListFilter{Ids: ids(posts[3:])}).Items))
+	require.ElementsMatch(t, ids(posts[4:]), ids(list(entity.GetListFilter{Statuses: []string{entity.StatusDraft}}).Items))
+	require.Len(t, list(entity.GetListFilter{UserId: env.UserId}).Items, 5)
+	require.Empty(t, list(entity.GetListFilter{UserId: uuid.NewString()}).Items)
+
+	_, err := env.Posts.List(ctx, &entity.GetListFilter{
+		Page: 1, Limit: 10, Sort: []entity.SortField{{Field: "content"}},
+	})
+	require.ErrorIs(t, err, entity.ErrValidation)
+}
+
+func testListByCursor(ctx context.Context, t *testing.T, env Env) {
+	category := unique("c")
+
+	posts := []*entity.Post{
+		create(ctx, t, env, category),
+		create(ctx, t, env, category),
+		create(ctx, t, env, category),
+	}
+
+	// The feed is newest first, ties broken by id.
+	sort.Slice(posts, func(i, j int) bool {
+		a, err := posts[i].Keyset()
+		require.NoError(t, err)
+		b, err := posts[j].Keyset()
+		require.NoError(t, err)
+
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+
+		return a.Id > b.Id
+	})
+
+	filter := entity.GetListFilter{Categories: []string{category}, Limit: 2}
+
+	first, err := env.Posts.ListByCursor(ctx, &filter)
+	require.NoError(t, err)
+	require.Equal(t, ids(posts[:2]), ids(first.Items))
+	require.True(t, first.HasMore)
+
+	keyset, err := first.Items[1].Keyset()
+	require.NoError(t, err)
+	filter.Keyset = &keyset
+
+	second, err := env.Posts.ListByCursor(ctx, &filter)
+	require.NoError(t, err)
+	require.Equal(t, ids(posts[2:]), ids(second.Items))
+	require.False(t, second.HasMore)
+
+	keyset, err = second.Items[0].Keyset()
+	require.NoError(t, err)
+	filter.Keyset, filter.Backward = &keyset, true
+
+	back, err := env.Posts.ListByCursor(ctx, &filter)
+	require.NoError(t, err)
+	require.Equal(t, ids(posts[:2]), ids(back.Items))
+	require.False(t, back.HasMore)
+}
+
+func testSearch(ctx context.Context, t *testing.T, env Env) {
+	category := unique("c")
+	word := unique("w")
+
+	apple := create(ctx, t, env, category, func(p *entity.Post) {
+		p.Title, p.Content = "Apple "+word, "An apple a day keeps "+word+" away"
+	})
+	banana := create(ctx, t, env, category, func(p *entity.Post) {
+		p.Title, p.Content = "Banana", "Bananas and "+word
+	})
+	create(ctx, t, env, category, func(p *entity.Post) { p.Title, p.Content = "Cherry", "No match here" })
+
+	search := func(query string) *entity.SearchResults {
+		t.Helper()
+
+		res, err := env.Posts.Search(ctx, &entity.SearchFilter{
+			GetListFilter: entity.GetListFilter{Categories: []string{category}, Page: 1, Limit: 10, WithTotal: true},
+			Query:         query,
+		})
+		require.NoError(t, err)
+
+		return res
+	}
+
+	res := search(word)
+	require.Equal(t, int64(2), *res.Total)
+	require.Len(t, res.Items, 2)
+	require.Equal(t, apple.Id, res.Items[0].Id, "more matches rank first")
+	require.Greater(t, res.Items[0].Rank, float32(0))
+	require.Contains(t, res.Items[0].Headline, "<mark>")
+
+	res = search(word + " -apple")
+	require.Len(t, res.Items, 1)
+	require.Equal(t, banana.Id, res.Items[0].Id)
+}
+
+func testTags(ctx context.Context, t *testing.T, env Env) {
+	category := unique("c")
+	prefix := unique("t")
+	goTag, sqlTag := prefix+"-go", prefix+"-sql"
+
+	create(ctx, t, env, category, func(p *entity.Post) { p.Tags = []string{goTag, sqlTag} })
+	create(ctx, t, env, category, func(p *entity.Post) { p.Tags = []string{goTag} })
+	create(ctx, t, env, category, func(p *entity.Post) { p.Tags, p.Status = []string{sqlTag}, entity.StatusDraft })
+
+	tags, err := env.Posts.ListTags(ctx, &entity.TagFilter{Prefix: prefix, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []*entity.Tag{{Name: goTag, Count: 2}, {Name: sqlTag, Count: 1}}, tags.Items)
+
+	tags, err = env.Posts.ListTags(ctx, &entity.TagFilter{Prefix: prefix, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tags.Count)
+}
+
+func testRevisions(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"))
+
+	for version := int64(1); version <= 3; version++ {
+		title := "Title " + string(rune('A'+version))
+
+		_, err := env.Posts.Patch(ctx, post.Id, &entity.PostPatch{Title: &title, Version: version})
+		require.NoError(t, err)
+	}
+
+	versions := func(page int64) ([]int64, bool) {
+		res, err := env.Posts.ListRevisions(ctx, &entity.RevisionFilter{PostId: post.Id, Page: page, Limit: 2})
+		require.NoError(t, err)
+
+		var versions []int64
+		for _, r := range res.Items {
+			versions = append(versions, r.Version)
+		}
+
+		return versions, res.HasMore
+	}
+
+	page, hasMore := versions(1)
+	require.Equal(t, []int64{3, 2}, page)
+	require.True(t, hasMore)
+
+	page, hasMore = versions(2)
+	require.Equal(t, []int64{1}, page)
+	require.False(t, hasMore)
+}
+
+func testPurge(ctx context.Context, t *testing.T, env Env) {
+	category := unique("c")
+	deleted := create(ctx, t, env, category)
+	kept := create(ctx, t, env, category)
+
+	require.NoError(t, env.Posts.Delete(ctx, deleted.Id, 1))
+
+	_, err := env.Posts.Purge(ctx, time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, env.Posts.Restore(ctx, deleted.Id), "deleted within the retention")
+	require.NoError(t, env.Posts.Delete(ctx, deleted.Id, 3))
+
+	// A negative retention purges whatever is deleted so far.
+	purged, err := env.Posts.Purge(ctx, -time.Minute)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, purged, int64(1))
+
+	require.ErrorIs(t, env.Posts.Restore(ctx, deleted.Id), entity.ErrNotFound)
+	get(ctx, t, env, kept.Id)
+}
+
+func testComments(ctx context.Context, t *testing.T, env Env) {
+	post := create(ctx, t, env, unique("c"))
+	other := create(ctx, t, env, unique("c"))
+
+	comment := func(parentId, content string) *entity.Comment {
+		t.Helper()
+
+		c, err := env.Comments.Create(ctx, &entity.Comment{
+			PostId: post.Id, ParentId: parentId, UserId: env.UserId, Content: content,
+		})
+		require.NoError(t, err)
+
+		return c
+	}
+
+	root := comment("", "root")
+	reply := comment(root.Id, "reply")
+	second := comment("", "second")
+
+	require.Equal(t, root.Id, reply.ParentId)
+	require.Equal(t, int64(3), get(ctx, t, env, post.Id).CommentsCount)
+
+	_, err := env.Comments.Create(ctx, &entity.Comment{
+		PostId: other.Id, ParentId: root.Id, UserId: env.UserId, Content: "elsewhere",
+	})
+	require.ErrorIs(t, err, entity.ErrValidation, "replies stay on the parent's post")
+
+	_, err = env.Comments.Create(ctx, &entity.Comment{PostId: uuid.NewString(), UserId: env.UserId, Content: "lost"})
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	deleted := create(ctx, t, env, unique("c"))
+	require.NoError(t, env.Posts.Delete(ctx, deleted.Id, 1))
+
+	_, err = env.Comments.Create(ctx, &entity.Comment{PostId: deleted.Id, UserId: env.UserId, Content: "late"})
+	require.ErrorIs(t, err, entity.ErrNotFound, "deleted posts take no comments")
+
+	list, err := env.Comments.List(ctx, &entity.CommentFilter{PostId: post.Id, Page: 1, Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), list.Total)
+	require.True(t, list.HasMore)
+	require.Len(t, list.Items, 2)
+
+	list, err = env.Comments.List(ctx, &entity.CommentFilter{PostId: post.Id, ParentId: root.Id, Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, reply.Id, list.Items[0].Id)
+
+	second.Content = "edited"
+
+	updated, err := env.Comments.Update(ctx, second)
+	require.NoError(t, err)
+	require.Equal(t, "edited", updated.Content)
+	require.NotEmpty(t, updated.UpdatedAt)
+
+	_, err = env.Comments.Get(ctx, other.Id, second.Id)
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	require.NoError(t, env.Comments.Delete(ctx, post.Id, root.Id))
+	require.Equal(t, int64(1), get(ctx, t, env, post.Id).CommentsCount)
+
+	_, err = env.Comments.Get(ctx, post.Id, reply.Id)
+	require.ErrorIs(t, err, entity.ErrNotFound, "replies go with their parent")
+
+	require.ErrorIs(t, env.Comments.Delete(ctx, post.Id, root.Id), entity.ErrNotFound)
+}
+
+func testAPIKeys(ctx context.Context, t *testing.T, env Env) {
+	prefix := unique("key")
+
+	key, err := env.APIKeys.Create(ctx, &entity.APIKey{
+		Name:      "importer",
+		Prefix:    prefix,
+		Hash:      []byte("hash"),
+		Scopes:    []string{entity.ScopePostsRead, entity.ScopePostsWrite},
+		RateLimit: 60,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, key.Id)
+	require.NotEmpty(t, key.CreatedAt)
+	require.Empty(t, key.LastUsedAt)
+
+	_, err = env.APIKeys.Create(ctx, &entity.APIKey{Name: "copy", Prefix: prefix, Hash: []byte("hash")})
+	require.ErrorIs(t, err, entity.ErrConflict)
+
+	got, err := env.APIKeys.GetByPrefix(ctx, prefix)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+	require.Equal(t, []byte("hash"), got.Hash)
+
+	_, err = env.APIKeys.GetByPrefix(ctx, unique("missing"))
+	require.ErrorIs(t, err, entity.ErrNotFound)
+
+	used := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, env.APIKeys.TouchLastUsed(ctx, map[string]time.Time{key.Id: used}))
+	require.NoError(t, env.APIKeys.TouchLastUsed(ctx, map[string]time.Time{key.Id: used.Add(-time.Minute)}))
+
+	got, err = env.APIKeys.GetByPrefix(ctx, prefix)
+	require.NoError(t, err)
+	require.Equal(t, used.String(), got.LastUsedAt, "the latest use is kept")
+
+	require.NoError(t, env.APIKeys.Revoke(ctx, key.Id))
+
+	got, err = env.APIKeys.GetByPrefix(ctx, prefix)
+	require.NoError(t, err)
+	require.NotEmpty(t, got.RevokedAt)
+
+	require.NoError(t, env.APIKeys.Revoke(ctx, key.Id))
+
+	again, err := env.APIKeys.GetByPrefix(ctx, prefix)
+	require.NoError(t, err)
+	require.Equal(t, got.RevokedAt, again.RevokedAt, "revoking twice keeps the first time")
+
+	require.ErrorIs(t, env.APIKeys.Revoke(ctx, uuid.NewString()), entity.ErrNotFound)
+
+	list, err := env.APIKeys.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, list.Count, int64(len(list.Items)))
+	require.Contains(t, list.Items, again)
+}
+
+func testAPIKeyWrites(ctx context.Context, t *testing.T, env Env) {
+	key, err := env.APIKeys.Create(ctx, &entity.APIKey{
+		Name:   "importer",
+		Prefix: unique("key"),
+		Hash:   []byte("hash"),
+		Scopes: []string{entity.ScopePostsWrite, entity.ScopePostsImpersonate},
+	})
+	require.NoError(t, err)
+
+	post := create(ctx, t, env, unique("c"), func(p *entity.Post) { p.APIKeyId = key.Id })
+	require.Equal(t, key.Id, post.APIKeyId)
+	require.Equal(t, key.Id, get(ctx, t, env, post.Id).APIKeyId)
+	require.Empty(t, get(ctx, t, env, create(ctx, t, env, unique("c")).Id).APIKeyId)
+
+	comment, err := env.Comments.Create(ctx, &entity.Comment{
+		PostId:   post.Id,
+		UserId:   env.UserId,
+		APIKeyId: key.Id,
+		Content:  "imported",
+	})
+	require.NoError(t, err)
+	require.Equal(t, key.Id, comment.APIKeyId)
+
+	got, err := env.Comments.Get(ctx, post.Id, comment.Id)
+	require.NoError(t, err)
+	require.Equal(t, key.Id, got.APIKeyId)
+}
@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+// _revisionColumns are the columns scanned by scanRevision.
+const _revisionColumns = `
+		post_id,
+		version,
+		user_id,
+		title,
+		content,
+		category,
+		tags,
+		edited_at
+		`
+
+// snapshot saves the post as it is at version into post_revisions, before an
+// edit in the same transaction replaces it. Nothing is saved when the post
+// isn't at version; the edit then fails its own version check.
+func (p *PostRepo) snapshot(ctx context.Context, id string, version int64) error {
+	q, args, err := p.Builder.Insert("post_revisions").
+		Columns("post_id", "version", "user_id", "title", "content", "category", "tags", "edited_at").
+		Select(squirrel.Select("id", "version", "user_id", "title", "content", "category").
+			Column("ARRAY(" + _postTagNames + " ORDER BY t.name)").
+			Column("COALESCE(updated_at, created_at)").
+			From("posts").
+			Where(squirrel.Eq{"id": id, "version": version, "deleted_at": nil})).
+		Suffix("ON CONFLICT (post_id, version) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("p.Builder: %w", err)
+	}
+
+	if _, err := p.Querier(ctx).Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("Exec: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// ListRevisions returns the saved revisions of a post, newest first -.
+func (p *PostRepo) ListRevisions(ctx context.Context, req *entity.RevisionFilter) (*entity.Revisions, error) {
+	q, args, err := p.Builder.Select(_revisionColumns).From("post_revisions").
+		Where(squirrel.Eq{"post_id": req.PostId}).
+		OrderBy("version DESC").
+		Offset(uint64((req.Page - 1) * req.Limit)).
+		Limit(uint64(req.Limit) + 1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListRevisions - p.Builder: %w", err)
+	}
+
+	rows, err := p.Querier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListRevisions - p.Querier.Query: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	revisions := entity.Revisions{Page: req.Page, Limit: req.Limit}
+
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("PostRepo - ListRevisions row.Scan: %w", translateError(err))
+		}
+
+		revisions.Items = append(revisions.Items, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PostRepo - ListRevisions rows.Err: %w", translateError(err))
+	}
+
+	if int64(len(revisions.Items)) > req.Limit {
+		revisions.HasMore = true
+		revisions.Items = revisions.Items[:req.Limit]
+	}
+	revisions.Count = int64(len(revisions.Items))
+
+	return &revisions, nil
+}
+
+// GetRevision returns the saved revision of a post at version -.
+func (p *PostRepo) GetRevision(ctx context.Context, postId string, version int64) (*entity.Revision, error) {
+	q, args, err := p.Builder.Select(_revisionColumns).From("post_revisions").
+		Where(squirrel.Eq{"post_id": postId, "version": version}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - GetRevision - p.Builder: %w", err)
+	}
+
+	revision, err := scanRevision(p.Querier(ctx).QueryRow(ctx, q, args...))
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - GetRevision row.Scan: %w", translateError(err))
+	}
+
+	return revision, nil
+}
+
+// scanRevision scans the columns of _revisionColumns.
+func scanRevision(row pgx.Row) (*entity.Revision, error) {
+	var (
+		revision entity.Revision
+		editedAt time.Time
+	)
+
+	if err := row.Scan(&revision.PostId, &revision.Version, &revision.UserId, &revision.Title,
+		&revision.Content, &revision.Category, &revision.Tags, &editedAt); err != nil {
+		return nil, err
+	}
+
+	revision.EditedAt = editedAt.String()
+
+	return &revision, nil
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// _postTagNames selects the tag names of the current posts row.
+const _postTagNames = `SELECT t.name::text FROM post_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = posts.id`
+
+// setTags replaces the tags of a post, creating the tags that
+// don't exist yet.
+func (p *PostRepo) setTags(ctx context.Context, postId string, tags []string) error {
+	q, args, err := p.Builder.Delete("post_tags").Where(squirrel.Eq{"post_id": postId}).ToSql()
+	if err != nil {
+		return fmt.Errorf("p.Builder: %w", err)
+	}
+	if _, err := p.Querier(ctx).Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("clear tags Exec: %w", translateError(err))
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	q, args, err = p.Builder.Insert("tags").Columns("name").
+		Select(squirrel.Select().Column("unnest(?::text[])", tags)).
+		Suffix("ON CONFLICT (name) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("p.Builder: %w", err)
+	}
+	if _, err := p.Querier(ctx).Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("create tags Exec: %w", translateError(err))
+	}
+
+	q, args, err = p.Builder.Insert("post_tags").Columns("post_id", "tag_id").
+		Select(squirrel.Select().Column("?::uuid", postId).Column("id").From("tags").Where(squirrel.Eq{"name": tags})).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("p.Builder: %w", err)
+	}
+	if _, err := p.Querier(ctx).Exec(ctx, q, args...); err != nil {
+		return fmt.Errorf("link tags Exec: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// ListTags returns the tags of published posts with their usage counts, most used first -.
+func (p *PostRepo) ListTags(ctx context.Context, req *entity.TagFilter) (*entity.Tags, error) {
+	query := p.Builder.Select("t.name", "COUNT(*) AS uses").
+		From("tags t").
+		Join("post_tags pt ON pt.tag_id = t.id").
+		Join("posts ON posts.id = pt.post_id").
+		Where(squirrel.Eq{"posts.status": entity.StatusPublished, "posts.deleted_at": nil}).
+		GroupBy("t.name").
+		OrderBy("uses DESC", "t.name ASC").
+		Limit(uint64(req.Limit))
+	if req.Prefix != "" {
+		query = query.Where(squirrel.Like{"t.name": escapeLike(req.Prefix) + "%"})
+	}
+
+	q, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListTags - p.Builder: %w", err)
+	}
+
+	rows, err := p.Querier(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PostRepo - ListTags - p.Querier.Query: %w", translateError(err))
+	}
+	defer rows.Close()
+
+	tags := entity.Tags{}
+
+	for rows.Next() {
+		var tag entity.Tag
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, fmt.Errorf("PostRepo - ListTags row.Scan: %w", translateError(err))
+		}
+
+		tags.Items = append(tags.Items, &tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PostRepo - ListTags rows.Err: %w", translateError(err))
+	}
+	tags.Count = int64(len(tags.Items))
+
+	return &tags, nil
+}
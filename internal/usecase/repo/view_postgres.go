@@ -0,0 +1,35 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// AddViews adds views to the counters of the posts, all in one statement.
+// Posts deleted since don't count.
+func (p *PostRepo) AddViews(ctx context.Context, views map[string]int64) error {
+	ids := make([]string, 0, len(views))
+	counts := make([]int64, 0, len(views))
+	for id, n := range views {
+		ids = append(ids, id)
+		counts = append(counts, n)
+	}
+
+	q, args, err := p.Builder.Update("posts").
+		Set("views", squirrel.Expr("posts.views + v.n")).
+		FromSelect(squirrel.Select().Column("unnest(?::uuid[]) AS id", ids).Column("unnest(?::bigint[]) AS n", counts), "v").
+		Where("posts.id = v.id AND posts.deleted_at IS NULL").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("PostRepo - AddViews - p.Builder: %w", err)
+	}
+
+	_, err = p.Querier(ctx).Exec(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("PostRepo - AddViews - p.Querier.Exec: %w", translateError(err))
+	}
+
+	return nil
+}
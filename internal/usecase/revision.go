@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/pkg/textdiff"
+)
+
+// List Revisions of a post, newest first
+func (p *PostUseCase) ListRevisions(ctx context.Context, req *entity.RevisionFilter) (*entity.Revisions, error) {
+	if _, err := p.viewablePost(ctx, req.PostId); err != nil {
+		return nil, fmt.Errorf("PostUseCase - ListRevisions - p.viewablePost: %w", err)
+	}
+
+	revisions, err := p.repo.ListRevisions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - ListRevisions - p.repo: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// Get Revision of a post; the current version counts as its latest revision
+func (p *PostUseCase) GetRevision(ctx context.Context, postId string, version int64) (*entity.Revision, error) {
+	revision, err := p.revision(ctx, postId, version)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - GetRevision - p.revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// Diff Revisions of a post, from one version to another
+func (p *PostUseCase) DiffRevisions(ctx context.Context, postId string, from, to int64) (*entity.RevisionDiff, error) {
+	a, err := p.revision(ctx, postId, from)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - DiffRevisions - p.revision: %w", err)
+	}
+
+	b, err := p.revision(ctx, postId, to)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - DiffRevisions - p.revision: %w", err)
+	}
+
+	diff := &entity.RevisionDiff{
+		PostId:      postId,
+		From:        from,
+		To:          to,
+		TagsAdded:   difference(b.Tags, a.Tags),
+		TagsRemoved: difference(a.Tags, b.Tags),
+	}
+
+	if a.Title != b.Title {
+		diff.Title = &entity.FieldChange{From: a.Title, To: b.Title}
+	}
+	if a.Category != b.Category {
+		diff.Category = &entity.FieldChange{From: a.Category, To: b.Category}
+	}
+
+	for _, edit := range textdiff.Lines(a.Content, b.Content) {
+		diff.Content = append(diff.Content, entity.LineDiff{Op: string(edit.Op), Text: edit.Text})
+	}
+
+	return diff, nil
+}
+
+// Revert Post to the content of an earlier revision. Like any edit it needs
+// the current version, and the content it replaces becomes a revision too.
+// Reverting to the current version is a conflict: there is nothing to revert
+func (p *PostUseCase) RevertPost(ctx context.Context, id string, revision, version int64) (*entity.Post, error) {
+	if version < 1 {
+		return nil, fmt.Errorf("PostUseCase - Revert: %w", versionRequired())
+	}
+
+	var post *entity.Post
+
+	err := p.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := p.repo.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("p.repo.Get: %w", err)
+		}
+
+		if revision == current.Version {
+			return fmt.Errorf("%w: revision %d is the current version", entity.ErrConflict, revision)
+		}
+
+		old, err := p.repo.GetRevision(ctx, id, revision)
+		if err != nil {
+			return fmt.Errorf("p.repo.GetRevision: %w", err)
+		}
+
+		post, err = p.PatchPost(ctx, id, &entity.PostPatch{
+			Title:    &old.Title,
+			Content:  &old.Content,
+			Category: &old.Category,
+			Tags:     &old.Tags,
+			Version:  version,
+		})
+		if err != nil {
+			return fmt.Errorf("p.PatchPost: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - Revert - %w", err)
+	}
+
+	return post, nil
+}
+
+// revision returns the revision of a post at version, which is the post
+// itself when version is the current one, if the caller may see the post.
+func (p *PostUseCase) revision(ctx context.Context, postId string, version int64) (*entity.Revision, error) {
+	post, err := p.viewablePost(ctx, postId)
+	if err != nil {
+		return nil, fmt.Errorf("p.viewablePost: %w", err)
+	}
+
+	if version == post.Version {
+		return post.Revision(), nil
+	}
+
+	revision, err := p.repo.GetRevision(ctx, postId, version)
+	if err != nil {
+		return nil, fmt.Errorf("p.repo.GetRevision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// difference returns the items of a that are not in b.
+func difference(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, s := range b {
+		in[s] = true
+	}
+
+	diff := []string{}
+	for _, s := range a {
+		if !in[s] {
+			diff = append(diff, s)
+		}
+	}
+
+	return diff
+}
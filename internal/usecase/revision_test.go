@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRevisions(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	current := &entity.Post{
+		Id:       "post-id",
+		Version:  3,
+		Title:    "New title",
+		Content:  "first\nsecond\nthird",
+		Category: "Nature",
+		Tags:     []string{"go", "sql"},
+	}
+	old := &entity.Revision{
+		PostId:   "post-id",
+		Version:  1,
+		Title:    "Old title",
+		Content:  "first\n2nd\nthird",
+		Category: "Nature",
+		Tags:     []string{"go", "web"},
+	}
+
+	repo.EXPECT().Get(context.Background(), "post-id").Return(current, nil).Times(2)
+	repo.EXPECT().GetRevision(context.Background(), "post-id", int64(1)).Return(old, nil)
+
+	diff, err := post.DiffRevisions(context.Background(), "post-id", 1, 3)
+
+	require.NoError(t, err)
+	require.Equal(t, &entity.FieldChange{From: "Old title", To: "New title"}, diff.Title)
+	require.Nil(t, diff.Category)
+	require.Equal(t, []string{"sql"}, diff.TagsAdded)
+	require.Equal(t, []string{"web"}, diff.TagsRemoved)
+	require.Equal(t, []entity.LineDiff{
+		{Op: "equal", Text: "first"},
+		{Op: "delete", Text: "2nd"},
+		{Op: "insert", Text: "second"},
+		{Op: "equal", Text: "third"},
+	}, diff.Content)
+}
+
+func TestRevertPost(t *testing.T) {
+	t.Parallel()
+
+	post, repo := post(t)
+
+	old := &entity.Revision{
+		PostId:   "post-id",
+		Version:  1,
+		Title:    "Old title",
+		Content:  "Old content",
+		Category: "Nature",
+		Tags:     []string{"go"},
+	}
+	reverted := &entity.Post{Id: "post-id", Version: 4}
+
+	repo.EXPECT().GetRevision(context.Background(), "post-id", int64(1)).Return(old, nil)
+	repo.EXPECT().Get(context.Background(), "post-id").Return(&entity.Post{Id: "post-id", Version: 3}, nil).Times(2)
+	repo.EXPECT().Patch(context.Background(), "post-id", &entity.PostPatch{
+		Title:    &old.Title,
+		Content:  &old.Content,
+		Category: &old.Category,
+		Tags:     &[]string{"go"},
+		Version:  3,
+	}).Return(reverted, nil)
+
+	res, err := post.RevertPost(context.Background(), "post-id", 1, 3)
+
+	require.NoError(t, err)
+	require.Equal(t, reverted, res)
+
+	_, err = post.RevertPost(context.Background(), "post-id", 1, 0)
+	require.ErrorIs(t, err, entity.ErrValidation)
+
+	repo.EXPECT().Get(context.Background(), "post-id").Return(&entity.Post{Id: "post-id", Version: 4}, nil)
+
+	_, err = post.RevertPost(context.Background(), "post-id", 4, 4)
+	require.ErrorIs(t, err, entity.ErrConflict, "the current version has nothing to revert")
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"sync"
+	"time"
+)
+
+// _maxViewers bounds the viewers a viewBuffer remembers for deduplication.
+const _maxViewers = 100000
+
+// viewBuffer counts post views in memory until they are flushed, so reads
+// don't turn into a write each.
+type viewBuffer struct {
+	mu      sync.Mutex
+	window  time.Duration
+	pending map[string]int64
+	seen    map[viewKey]time.Time
+	maxSeen int
+}
+
+// viewKey is a viewer of a post, for deduplication.
+type viewKey struct {
+	postId string
+	viewer string
+}
+
+func newViewBuffer() *viewBuffer {
+	return &viewBuffer{
+		pending: make(map[string]int64),
+		seen:    make(map[viewKey]time.Time),
+		maxSeen: _maxViewers,
+	}
+}
+
+// add counts a view of the post unless viewer already viewed it within the
+// window, and returns the views of the post not flushed yet. Once maxSeen
+// viewers are remembered, new ones are counted without being remembered
+// until take forgets some.
+func (b *viewBuffer) add(postId, viewer string, now time.Time) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.window > 0 && viewer != "" {
+		key := viewKey{postId: postId, viewer: viewer}
+		if at, ok := b.seen[key]; ok && now.Sub(at) < b.window {
+			return b.pending[postId]
+		}
+
+		if _, ok := b.seen[key]; ok || len(b.seen) < b.maxSeen {
+			b.seen[key] = now
+		}
+	}
+
+	b.pending[postId]++
+
+	return b.pending[postId]
+}
+
+// take returns the pending views and starts counting anew. Viewers whose
+// window has passed are forgotten.
+func (b *viewBuffer) take(now time.Time) map[string]int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, at := range b.seen {
+		if now.Sub(at) >= b.window {
+			delete(b.seen, key)
+		}
+	}
+
+	views := b.pending
+	b.pending = make(map[string]int64, len(views))
+
+	return views
+}
+
+// putBack returns views that could not be flushed to the buffer.
+func (b *viewBuffer) putBack(views map[string]int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, n := range views {
+		b.pending[id] += n
+	}
+}
+
+// View Post gets a post and counts a view of it by viewer, who counts once per
+// view window. Posts that aren't published are only found by those allowed to
+// see them, and their views aren't counted. Counted views are saved by
+// FlushViews and included in the returned post right away.
+func (p *PostUseCase) ViewPost(ctx context.Context, id, viewer string) (*entity.Post, error) {
+	post, err := p.viewablePost(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("PostUseCase - View - p.viewablePost: %w", err)
+	}
+
+	if post.Status == entity.StatusPublished {
+		post.Views += p.views.add(post.Id, viewer, time.Now())
+	}
+
+	return post, nil
+}
+
+// FlushViews saves the views counted since the last flush and returns how many.
+// Views that fail to save are kept for the next flush.
+func (p *PostUseCase) FlushViews(ctx context.Context) (int64, error) {
+	views := p.views.take(time.Now())
+	if len(views) == 0 {
+		return 0, nil
+	}
+
+	err := p.repo.AddViews(ctx, views)
+	if err != nil {
+		p.views.putBack(views)
+
+		return 0, fmt.Errorf("PostUseCase - FlushViews - p.repo: %w", err)
+	}
+
+	var flushed int64
+	for _, n := range views {
+		flushed += n
+	}
+
+	return flushed, nil
+}
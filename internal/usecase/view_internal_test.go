@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewBufferBoundsViewers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		b   = newViewBuffer()
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+	b.window, b.maxSeen = time.Hour, 2
+
+	require.Equal(t, int64(1), b.add("post", "a", now))
+	require.Equal(t, int64(2), b.add("post", "b", now))
+	require.Equal(t, int64(3), b.add("post", "c", now))
+	require.Len(t, b.seen, 2)
+
+	require.Equal(t, int64(4), b.add("post", "c", now), "viewers past the bound aren't remembered")
+	require.Equal(t, int64(4), b.add("post", "a", now.Add(time.Minute)), "remembered viewers still count once")
+
+	require.Equal(t, map[string]int64{"post": 4}, b.take(now.Add(time.Minute+time.Hour)))
+	require.Empty(t, b.seen, "take forgets viewers whose window passed")
+
+	later := now.Add(2 * time.Hour)
+	require.Equal(t, int64(1), b.add("post", "c", later))
+	require.Equal(t, int64(1), b.add("post", "c", later), "room was made for new viewers")
+}
@@ -0,0 +1,66 @@
+package usecase_test
+
+import (
+	"context"
+	"fourth-exam/post-service-clean-arch/internal/entity"
+	"fourth-exam/post-service-clean-arch/internal/usecase"
+	"fourth-exam/post-service-clean-arch/pkg/cursor"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewPost(t *testing.T) {
+	t.Parallel()
+
+	mockCtl := gomock.NewController(t)
+	repo := NewMockPostRepo(mockCtl)
+	post := usecase.New(repo, cursor.New("secret"), noTx{}, usecase.ViewWindow(time.Hour))
+
+	published := func() *entity.Post {
+		return &entity.Post{Id: "post-id", Status: entity.StatusPublished, Views: 10}
+	}
+	draft := &entity.Post{Id: "draft-id", UserId: "d0b69f3b-2021-4d91-8e13-c243d9eb5292", Status: entity.StatusDraft}
+
+	repo.EXPECT().Get(context.Background(), "post-id").DoAndReturn(func(context.Context, string) (*entity.Post, error) {
+		return published(), nil
+	}).Times(3)
+	repo.EXPECT().Get(gomock.Any(), "draft-id").Return(draft, nil).Times(2)
+
+	res, err := post.ViewPost(context.Background(), "post-id", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, int64(11), res.Views)
+
+	res, err = post.ViewPost(context.Background(), "post-id", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, int64(11), res.Views, "repeated view within the window")
+
+	res, err = post.ViewPost(context.Background(), "post-id", "10.0.0.2")
+	require.NoError(t, err)
+	require.Equal(t, int64(12), res.Views)
+
+	_, err = post.ViewPost(context.Background(), "draft-id", "10.0.0.1")
+	require.ErrorIs(t, err, entity.ErrNotFound, "drafts are hidden from anonymous callers")
+
+	author := entity.ContextWithPrincipal(context.Background(), &entity.Principal{UserId: draft.UserId})
+	res, err = post.ViewPost(author, "draft-id", "10.0.0.1")
+	require.NoError(t, err)
+	require.Zero(t, res.Views, "views of drafts aren't counted")
+
+	repo.EXPECT().AddViews(context.Background(), map[string]int64{"post-id": 2}).Return(errInternalServerErr)
+
+	_, err = post.FlushViews(context.Background())
+	require.ErrorIs(t, err, errInternalServerErr)
+
+	repo.EXPECT().AddViews(context.Background(), map[string]int64{"post-id": 2}).Return(nil)
+
+	flushed, err := post.FlushViews(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), flushed, "views kept after a failed flush")
+
+	flushed, err = post.FlushViews(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, flushed)
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS post_reactions (
+    post_id uuid NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction TEXT NOT NULL CHECK (reaction IN ('like', 'dislike')),
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE,
+    PRIMARY KEY (post_id, user_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE posts DROP COLUMN IF EXISTS comments_count;
+
+DROP TABLE IF EXISTS comments;
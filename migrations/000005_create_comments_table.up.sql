@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id uuid PRIMARY KEY,
+    post_id uuid NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    parent_id uuid REFERENCES comments(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS comments_post_id_created_at_idx ON comments (post_id, created_at, id);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS comments_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'scheduled', 'published', 'archived')),
+    ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITHOUT TIME ZONE;
+
+-- Posts created before the lifecycle existed were live right away; deleted
+-- ones stay drafts, so restoring one doesn't publish it.
+--
+-- created_at holds the wall-clock time of the server that wrote it, while
+-- published_at is UTC, so the backfill converts it. The server's zone is taken
+-- to be the session's: when they differ, run the migration with the server's
+-- zone as a connection parameter, e.g. '...?sslmode=disable&timezone=Europe/Moscow'.
+UPDATE posts
+SET status       = 'published',
+    published_at = created_at AT TIME ZONE current_setting('TimeZone') AT TIME ZONE 'UTC'
+WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS posts_status_published_at_idx ON posts (status, published_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS post_revisions (
+    post_id uuid NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    user_id uuid NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    category TEXT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    edited_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (post_id, version)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS api_key_id;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS api_key_id;
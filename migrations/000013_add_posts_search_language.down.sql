@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS posts_search_vector_idx;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE posts ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
+
+ALTER TABLE posts DROP COLUMN IF EXISTS search_language;
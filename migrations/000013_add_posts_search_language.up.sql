@@ -0,0 +1,15 @@
+-- The search vector is built in the language of each post, which the service
+-- sets to its configured SEARCH_LANGUAGE.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_language regconfig NOT NULL DEFAULT 'english';
+
+DROP INDEX IF EXISTS posts_search_vector_idx;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE posts ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_language, coalesce(title, '')), 'A') ||
+        setweight(to_tsvector(search_language, coalesce(content, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx ON posts USING GIN (search_vector);
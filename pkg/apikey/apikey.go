@@ -0,0 +1,69 @@
+// Package apikey generates and checks the API keys services authenticate
+// with. A key is "psk_<prefix>_<secret>": the prefix finds the key and is
+// safe to show and log, the secret proves it and is only stored hashed.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	_scheme      = "psk_"
+	_prefixBytes = 8
+	_secretBytes = 32
+)
+
+// ErrMalformed is returned for strings that aren't API keys.
+var ErrMalformed = errors.New("apikey: malformed")
+
+// Generate returns a new key, its prefix and the hash of its secret.
+func Generate() (key, prefix string, hash []byte, err error) {
+	raw := make([]byte, _prefixBytes+_secretBytes)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", nil, fmt.Errorf("apikey - Generate - rand.Read: %w", err)
+	}
+
+	prefix = hex.EncodeToString(raw[:_prefixBytes])
+	secret := base64.RawURLEncoding.EncodeToString(raw[_prefixBytes:])
+
+	return _scheme + prefix + "_" + secret, prefix, Hash(secret), nil
+}
+
+// Parse splits key into its prefix and secret.
+func Parse(key string) (prefix, secret string, err error) {
+	rest, ok := strings.CutPrefix(key, _scheme)
+	if !ok {
+		return "", "", ErrMalformed
+	}
+
+	prefix, secret, ok = strings.Cut(rest, "_")
+	if !ok || len(prefix) != 2*_prefixBytes || secret == "" {
+		return "", "", ErrMalformed
+	}
+
+	if _, err := hex.DecodeString(prefix); err != nil {
+		return "", "", ErrMalformed
+	}
+
+	return prefix, secret, nil
+}
+
+// Hash returns the hash of secret that is stored instead of it. Secrets are
+// random, so a plain SHA-256 is as good as a password hash here.
+func Hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+
+	return sum[:]
+}
+
+// Verify reports whether secret matches hash, in constant time.
+func Verify(secret string, hash []byte) bool {
+	return subtle.ConstantTimeCompare(Hash(secret), hash) == 1
+}
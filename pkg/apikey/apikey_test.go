@@ -0,0 +1,46 @@
+package apikey_test
+
+import (
+	"strings"
+	"testing"
+
+	"fourth-exam/post-service-clean-arch/pkg/apikey"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAndParse(t *testing.T) {
+	t.Parallel()
+
+	key, prefix, hash, err := apikey.Generate()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, "psk_"+prefix+"_"))
+
+	gotPrefix, secret, err := apikey.Parse(key)
+	require.NoError(t, err)
+	require.Equal(t, prefix, gotPrefix)
+	require.True(t, apikey.Verify(secret, hash))
+	require.False(t, apikey.Verify(secret+"x", hash))
+
+	other, _, _, err := apikey.Generate()
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+}
+
+func TestParseMalformed(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{
+		"",
+		"secret",
+		"psk_",
+		"psk_0123456789abcdef",
+		"psk_0123456789abcdef_",
+		"psk_0123_secret",
+		"psk_0123456789abcdeg_secret",
+		"Bearer psk_0123456789abcdef_secret",
+	} {
+		_, _, err := apikey.Parse(key)
+		require.ErrorIs(t, err, apikey.ErrMalformed, key)
+	}
+}
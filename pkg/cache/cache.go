@@ -0,0 +1,18 @@
+// Package cache implements caches of byte values with expiry.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values under keys for a time to live. Implementations are safe
+// for concurrent use.
+type Cache interface {
+	// Get returns the value under key, and false when there is none or it expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the keys, ignoring missing ones.
+	Delete(ctx context.Context, keys ...string) error
+}
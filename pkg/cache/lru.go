@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const _defaultSize = 10000
+
+// LRU is an in-process Cache holding at most size values, evicting the least
+// recently used one to make room.
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+var _ Cache = (*LRU)(nil)
+
+// NewLRU -.
+func NewLRU(size int) *LRU {
+	if size <= 0 {
+		size = _defaultSize
+	}
+
+	return &LRU{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// Get -.
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	item := el.Value.(*lruItem)
+	if !time.Now().Before(item.expiresAt) {
+		c.remove(el)
+
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(el)
+
+	return item.value, true, nil
+}
+
+// Set -.
+func (c *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+
+	if el, ok := c.items[key]; ok {
+		item := el.Value.(*lruItem)
+		item.value, item.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruItem{key: key, value: value, expiresAt: expiresAt})
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+
+	return nil
+}
+
+// Delete -.
+func (c *LRU) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.remove(el)
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of values held, expired ones included until they
+// are looked up or evicted.
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *LRU) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruItem).key)
+}
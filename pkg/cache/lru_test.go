@@ -0,0 +1,108 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fourth-exam/post-service-clean-arch/pkg/cache"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, c cache.Cache, key string) (string, bool) {
+	t.Helper()
+
+	value, ok, err := c.Get(context.Background(), key)
+	require.NoError(t, err)
+
+	return string(value), ok
+}
+
+func set(t *testing.T, c cache.Cache, key, value string, ttl time.Duration) {
+	t.Helper()
+
+	require.NoError(t, c.Set(context.Background(), key, []byte(value), ttl))
+}
+
+func TestLRUGetSetDelete(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewLRU(10)
+
+	_, ok := get(t, c, "a")
+	require.False(t, ok)
+
+	set(t, c, "a", "1", time.Minute)
+	set(t, c, "b", "2", time.Minute)
+	set(t, c, "a", "3", time.Minute)
+
+	value, ok := get(t, c, "a")
+	require.True(t, ok)
+	require.Equal(t, "3", value, "set replaces the value")
+	require.Equal(t, 2, c.Len())
+
+	require.NoError(t, c.Delete(context.Background(), "a", "missing"))
+
+	_, ok = get(t, c, "a")
+	require.False(t, ok)
+	value, ok = get(t, c, "b")
+	require.True(t, ok)
+	require.Equal(t, "2", value)
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewLRU(2)
+
+	set(t, c, "a", "1", time.Minute)
+	set(t, c, "b", "2", time.Minute)
+	get(t, c, "a")
+	set(t, c, "c", "3", time.Minute)
+
+	require.Equal(t, 2, c.Len())
+	_, ok := get(t, c, "b")
+	require.False(t, ok, "b was used least recently")
+
+	for _, key := range []string{"a", "c"} {
+		_, ok := get(t, c, key)
+		require.True(t, ok, key)
+	}
+
+	set(t, c, "a", "4", time.Minute)
+	set(t, c, "d", "5", time.Minute)
+
+	_, ok = get(t, c, "c")
+	require.False(t, ok, "setting a uses it")
+}
+
+func TestLRUExpiry(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewLRU(10)
+
+	set(t, c, "expired", "1", 0)
+	set(t, c, "live", "2", time.Minute)
+	require.Equal(t, 2, c.Len(), "expired values are held until looked up")
+
+	_, ok := get(t, c, "expired")
+	require.False(t, ok)
+	require.Equal(t, 1, c.Len(), "looking an expired value up removes it")
+
+	set(t, c, "live", "3", 0)
+
+	_, ok = get(t, c, "live")
+	require.False(t, ok, "set replaces the expiry")
+}
+
+func TestNewLRUDefaultSize(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewLRU(0)
+
+	for i := 0; i < 100; i++ {
+		set(t, c, string(rune('a'+i)), "v", time.Minute)
+	}
+	require.Equal(t, 100, c.Len(), "a non-positive size falls back to the default")
+}
@@ -0,0 +1,70 @@
+package cursor
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is returned for malformed or tampered cursors.
+var ErrInvalid = errors.New("cursor: invalid")
+
+// Codec turns pagination positions into opaque tokens signed with HMAC-SHA256,
+// so clients can pass them back but not forge them.
+type Codec struct {
+	secret []byte
+}
+
+// New -.
+func New(secret string) *Codec {
+	return &Codec{secret: []byte(secret)}
+}
+
+// Encode marshals v to JSON and returns it as a signed, URL-safe token.
+func (c *Codec) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("cursor - Encode - json.Marshal: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies token and unmarshals its payload into v.
+func (c *Codec) Decode(token string, v interface{}) error {
+	encPayload, encMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encMAC)
+	if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+		return ErrInvalid
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return ErrInvalid
+	}
+
+	return nil
+}
+
+func (c *Codec) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(payload)
+
+	return h.Sum(nil)
+}
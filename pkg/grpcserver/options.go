@@ -0,0 +1,32 @@
+package grpcserver
+
+import (
+	"net"
+	"time"
+
+	pbgrpc "google.golang.org/grpc"
+)
+
+// Option -.
+type Option func(*Server)
+
+// Port -.
+func Port(port string) Option {
+	return func(s *Server) {
+		s.address = net.JoinHostPort("", port)
+	}
+}
+
+// UnaryInterceptors -.
+func UnaryInterceptors(interceptors ...pbgrpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.serverOptions = append(s.serverOptions, pbgrpc.ChainUnaryInterceptor(interceptors...))
+	}
+}
+
+// ShutDownTimeOut -.
+func ShutDownTimeOut(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout = timeout
+	}
+}
@@ -0,0 +1,77 @@
+package grpcserver
+
+import (
+	"net"
+	"time"
+
+	pbgrpc "google.golang.org/grpc"
+)
+
+const (
+	_defaultAddr            = ":9090"
+	_defaultShutdownTimeout = 3 * time.Second
+)
+
+// Server -.
+type Server struct {
+	App             *pbgrpc.Server
+	notify          chan error
+	address         string
+	serverOptions   []pbgrpc.ServerOption
+	shutdownTimeout time.Duration
+}
+
+// New creates the gRPC server. Services are registered on App before Start.
+func New(opts ...Option) *Server {
+	s := &Server{
+		notify:          make(chan error, 1),
+		address:         _defaultAddr,
+		shutdownTimeout: _defaultShutdownTimeout,
+	}
+
+	// Custom options ...
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.App = pbgrpc.NewServer(s.serverOptions...)
+
+	return s
+}
+
+// Start -.
+func (s *Server) Start() {
+	go func() {
+		ln, err := net.Listen("tcp", s.address)
+		if err == nil {
+			err = s.App.Serve(ln)
+		}
+
+		s.notify <- err
+		close(s.notify)
+	}()
+}
+
+// Notify -.
+func (s *Server) Notify() <-chan error {
+	return s.notify
+}
+
+// Shutdown waits for in-flight calls to finish, cutting them off after the
+// shutdown timeout.
+func (s *Server) Shutdown() error {
+	done := make(chan struct{})
+
+	go func() {
+		s.App.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(s.shutdownTimeout):
+		s.App.Stop()
+	}
+
+	return nil
+}
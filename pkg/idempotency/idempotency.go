@@ -0,0 +1,56 @@
+// Package idempotency remembers the responses to requests made with an
+// idempotency key, so a client retrying such a request gets the response to
+// the first attempt instead of running it again.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"time"
+)
+
+const (
+	_defaultTTL   = 24 * time.Hour
+	_defaultLease = time.Minute
+
+	// _sweepInterval is how often stores delete expired keys.
+	_sweepInterval = time.Minute
+)
+
+// Response is a response to replay.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Record is a request made with a key.
+type Record struct {
+	Fingerprint []byte
+	// Response is nil while the request is in flight.
+	Response *Response
+}
+
+// Store keeps the records of keys for the TTL of the store.
+type Store interface {
+	// Begin claims key for a request with fingerprint and returns nil, in
+	// which case the caller runs the request and then completes or releases
+	// the key. If the key is already claimed it returns its record instead.
+	// A claim not completed within the lease of the store lapses, so a
+	// request with the same fingerprint can claim the key again.
+	Begin(ctx context.Context, key string, fingerprint []byte) (*Record, error)
+	// Complete saves the response to the request that claimed key.
+	Complete(ctx context.Context, key string, res *Response) error
+	// Release drops the claim on key, so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// Fingerprint identifies a request by its method, target and body.
+func Fingerprint(method, target string, body []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(method + " " + target + "\n"))
+	h.Write(body)
+
+	return h.Sum(nil)
+}
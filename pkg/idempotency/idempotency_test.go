@@ -0,0 +1,123 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore runs the behaviour every Store shares on key.
+func testStore(t *testing.T, store Store, key string) {
+	t.Helper()
+
+	var (
+		ctx   = context.Background()
+		first = Fingerprint(http.MethodPost, "/v1/post/create", []byte(`{"title":"a"}`))
+		other = Fingerprint(http.MethodPost, "/v1/post/create", []byte(`{"title":"b"}`))
+		res   = &Response{
+			Status: http.StatusCreated,
+			Header: http.Header{"Content-Type": {"application/json"}},
+			Body:   []byte(`{"id":"1"}`),
+		}
+	)
+
+	rec, err := store.Begin(ctx, key, first)
+	require.NoError(t, err)
+	require.Nil(t, rec, "a new key is claimed")
+
+	rec, err = store.Begin(ctx, key, first)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	require.Nil(t, rec.Response, "the first request is in flight")
+
+	require.NoError(t, store.Release(ctx, key))
+
+	rec, err = store.Begin(ctx, key, first)
+	require.NoError(t, err)
+	require.Nil(t, rec, "a released key is claimed again")
+
+	require.NoError(t, store.Complete(ctx, key, res))
+
+	rec, err = store.Begin(ctx, key, first)
+	require.NoError(t, err)
+	require.Equal(t, &Record{Fingerprint: first, Response: res}, rec)
+
+	rec, err = store.Begin(ctx, key, other)
+	require.NoError(t, err)
+	require.Equal(t, first, rec.Fingerprint, "another request gets the record of the first")
+
+	require.NoError(t, store.Release(ctx, key))
+
+	rec, err = store.Begin(ctx, key, first)
+	require.NoError(t, err)
+	require.NotNil(t, rec.Response, "completed keys aren't released")
+}
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	testStore(t, NewMemory(), "key")
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		now   = time.Now()
+		store = NewMemory(TTL(time.Hour), Lease(time.Minute))
+		fp    = Fingerprint(http.MethodPost, "/", nil)
+	)
+	store.now = func() time.Time { return now }
+
+	rec, err := store.Begin(ctx, "key", fp)
+	require.NoError(t, err)
+	require.Nil(t, rec)
+
+	now = now.Add(time.Minute)
+
+	rec, err = store.Begin(ctx, "key", Fingerprint(http.MethodPost, "/", []byte("x")))
+	require.NoError(t, err)
+	require.NotNil(t, rec, "a lapsed claim is only taken over by the same request")
+
+	rec, err = store.Begin(ctx, "key", fp)
+	require.NoError(t, err)
+	require.Nil(t, rec, "the same request takes over a lapsed claim")
+
+	require.NoError(t, store.Complete(ctx, "key", &Response{Status: http.StatusCreated}))
+
+	now = now.Add(time.Hour)
+
+	rec, err = store.Begin(ctx, "key", fp)
+	require.NoError(t, err)
+	require.Nil(t, rec, "expired keys are forgotten")
+	require.Len(t, store.records, 1)
+}
+
+// TestPostgres runs against the disposable database at PG_URL, with the
+// migrations applied.
+func TestPostgres(t *testing.T) {
+	url := os.Getenv("PG_URL")
+	if url == "" {
+		t.Skip("PG_URL is not set")
+	}
+
+	pg, err := postgres.New(url)
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	key := "test:" + uuid.NewString()
+	t.Cleanup(func() {
+		_, err := pg.Pool.Exec(context.Background(), "DELETE FROM idempotency_keys WHERE key = $1", key)
+		require.NoError(t, err)
+	})
+
+	testStore(t, NewPostgres(pg), key)
+}
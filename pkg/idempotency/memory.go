@@ -0,0 +1,101 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps the records in memory, so retries only replay on the
+// instance of the service that ran the request.
+type Memory struct {
+	options
+
+	mu        sync.Mutex
+	records   map[string]*memoryRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryRecord struct {
+	Record
+
+	lockedUntil time.Time
+	expires     time.Time
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory -.
+func NewMemory(opts ...Option) *Memory {
+	return &Memory{
+		options: newOptions(opts),
+		records: make(map[string]*memoryRecord),
+		now:     time.Now,
+	}
+}
+
+// Begin -.
+func (m *Memory) Begin(_ context.Context, key string, fingerprint []byte) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	if r, ok := m.records[key]; ok && now.Before(r.expires) {
+		lapsed := r.Response == nil && !now.Before(r.lockedUntil) && bytes.Equal(r.Fingerprint, fingerprint)
+		if !lapsed {
+			rec := r.Record
+
+			return &rec, nil
+		}
+	}
+
+	m.records[key] = &memoryRecord{
+		Record:      Record{Fingerprint: fingerprint},
+		lockedUntil: now.Add(m.lease),
+		expires:     now.Add(m.ttl),
+	}
+
+	return nil, nil
+}
+
+// Complete -.
+func (m *Memory) Complete(_ context.Context, key string, res *Response) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.records[key]; ok && r.Response == nil {
+		r.Response = res
+	}
+
+	return nil
+}
+
+// Release -.
+func (m *Memory) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.records[key]; ok && r.Response == nil {
+		delete(m.records, key)
+	}
+
+	return nil
+}
+
+// sweep forgets the expired records, at most every _sweepInterval.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < _sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, r := range m.records {
+		if !now.Before(r.expires) {
+			delete(m.records, key)
+		}
+	}
+}
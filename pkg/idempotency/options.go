@@ -0,0 +1,35 @@
+package idempotency
+
+import "time"
+
+type options struct {
+	ttl   time.Duration
+	lease time.Duration
+}
+
+// Option -.
+type Option func(*options)
+
+// TTL sets how long a key is remembered, a day by default.
+func TTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// Lease sets how long a request may hold a key before completing it, a
+// minute by default.
+func Lease(lease time.Duration) Option {
+	return func(o *options) {
+		o.lease = lease
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{ttl: _defaultTTL, lease: _defaultLease}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
@@ -0,0 +1,150 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// _begin claims key $1 unless a live record holds it. Expired records are
+// replaced, and so are lapsed claims of the same request.
+const _begin = `INSERT INTO idempotency_keys AS k (key, fingerprint, locked_until, expires_at)
+	VALUES ($1, $2, now() + make_interval(secs => $3::float8), now() + make_interval(secs => $4::float8))
+	ON CONFLICT (key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint,
+		status = NULL,
+		header = NULL,
+		body = NULL,
+		locked_until = EXCLUDED.locked_until,
+		expires_at = EXCLUDED.expires_at
+	WHERE k.expires_at <= now()
+		OR (k.status IS NULL AND k.locked_until <= now() AND k.fingerprint = EXCLUDED.fingerprint)
+	RETURNING key`
+
+// _beginAttempts bounds the retries of Begin when the record it found is
+// released before it could be read.
+const _beginAttempts = 3
+
+// Postgres keeps the records in the idempotency_keys table, so a retry
+// replays on any instance of the service.
+type Postgres struct {
+	options
+
+	pg *postgres.Postgres
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+var _ Store = (*Postgres)(nil)
+
+// NewPostgres -.
+func NewPostgres(pg *postgres.Postgres, opts ...Option) *Postgres {
+	return &Postgres{options: newOptions(opts), pg: pg}
+}
+
+// Begin -.
+func (p *Postgres) Begin(ctx context.Context, key string, fingerprint []byte) (*Record, error) {
+	if err := p.sweep(ctx); err != nil {
+		return nil, err
+	}
+
+	var claimed string
+
+	for i := 0; i < _beginAttempts; i++ {
+		err := p.pg.Pool.QueryRow(ctx, _begin, key, fingerprint, p.lease.Seconds(), p.ttl.Seconds()).Scan(&claimed)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("idempotency - Postgres - Begin - p.pg.Pool.QueryRow: %w", err)
+		}
+
+		rec, err := p.get(ctx, key)
+		if err == nil {
+			return rec, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("idempotency - Postgres - Begin - p.get: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("idempotency - Postgres - Begin: key %q kept changing", key)
+}
+
+// Complete -.
+func (p *Postgres) Complete(ctx context.Context, key string, res *Response) error {
+	header, err := json.Marshal(res.Header)
+	if err != nil {
+		return fmt.Errorf("idempotency - Postgres - Complete - json.Marshal: %w", err)
+	}
+
+	_, err = p.pg.Pool.Exec(ctx,
+		"UPDATE idempotency_keys SET status = $2, header = $3, body = $4 WHERE key = $1 AND status IS NULL",
+		key, res.Status, header, res.Body)
+	if err != nil {
+		return fmt.Errorf("idempotency - Postgres - Complete - p.pg.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Release -.
+func (p *Postgres) Release(ctx context.Context, key string) error {
+	_, err := p.pg.Pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1 AND status IS NULL", key)
+	if err != nil {
+		return fmt.Errorf("idempotency - Postgres - Release - p.pg.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) get(ctx context.Context, key string) (*Record, error) {
+	var (
+		rec    Record
+		status *int
+		header []byte
+		body   []byte
+	)
+
+	err := p.pg.Pool.QueryRow(ctx,
+		"SELECT fingerprint, status, header, body FROM idempotency_keys WHERE key = $1", key,
+	).Scan(&rec.Fingerprint, &status, &header, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != nil {
+		rec.Response = &Response{Status: *status, Body: body}
+		if err := json.Unmarshal(header, &rec.Response.Header); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+	}
+
+	return &rec, nil
+}
+
+// sweep deletes the expired records, at most every _sweepInterval.
+func (p *Postgres) sweep(ctx context.Context) error {
+	p.mu.Lock()
+	if time.Since(p.lastSweep) < _sweepInterval {
+		p.mu.Unlock()
+
+		return nil
+	}
+	p.lastSweep = time.Now()
+	p.mu.Unlock()
+
+	_, err := p.pg.Pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE expires_at < now()")
+	if err != nil {
+		return fmt.Errorf("idempotency - Postgres - sweep - p.pg.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
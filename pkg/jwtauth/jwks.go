@@ -0,0 +1,112 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// jwk is the subset of RFC 7517 fields needed for RSA and P-256 keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS adds the signing keys of a JWKS file. Keys for other uses or
+// algorithms are skipped.
+func (v *Verifier) loadJWKS(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		switch {
+		case k.Kty == "RSA" && (k.Alg == "" || k.Alg == "RS256"):
+			key, err := k.rsa()
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k.Kid, err)
+			}
+
+			v.rsaKeys[k.Kid] = key
+		case k.Kty == "EC" && k.Crv == "P-256" && (k.Alg == "" || k.Alg == "ES256"):
+			key, err := k.ecdsa()
+			if err != nil {
+				return fmt.Errorf("key %q: %w", k.Kid, err)
+			}
+
+			v.ecKeys[k.Kid] = key
+		}
+	}
+
+	return nil
+}
+
+func (k *jwk) rsa() (*rsa.PublicKey, error) {
+	n, err := decodeInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("n: %w", err)
+	}
+
+	e, err := decodeInt(k.E)
+	if err != nil || !e.IsInt64() {
+		return nil, fmt.Errorf("e: invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *jwk) ecdsa() (*ecdsa.PublicKey, error) {
+	x, err := decodeInt(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+
+	y, err := decodeInt(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	if !key.Curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("point is not on P-256")
+	}
+
+	return key, nil
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
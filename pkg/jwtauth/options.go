@@ -0,0 +1,54 @@
+package jwtauth
+
+import "time"
+
+// Option -.
+type Option func(*Verifier)
+
+// HMACSecret accepts HS256 tokens signed with secret.
+func HMACSecret(secret string) Option {
+	return func(v *Verifier) {
+		if secret != "" {
+			v.hmacSecret = []byte(secret)
+		}
+	}
+}
+
+// PublicKey accepts RS256 or ES256 tokens signed by the PEM encoded RSA or
+// P-256 public key.
+func PublicKey(pem string) Option {
+	return func(v *Verifier) {
+		if pem != "" {
+			v.publicKeyPEM = pem
+		}
+	}
+}
+
+// JWKSFile accepts RS256 and ES256 tokens signed by the keys of a local JWKS
+// file, picked by the token's kid.
+func JWKSFile(path string) Option {
+	return func(v *Verifier) {
+		v.jwksFile = path
+	}
+}
+
+// Issuer requires the iss claim to be issuer.
+func Issuer(issuer string) Option {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// Audience requires the aud claim to contain audience.
+func Audience(audience string) Option {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// Leeway allows for clock skew when checking exp, nbf and iat.
+func Leeway(leeway time.Duration) Option {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
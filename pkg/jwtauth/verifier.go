@@ -0,0 +1,172 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const _defaultLeeway = 30 * time.Second
+
+// MinHMACSecretLength is the shortest HMAC secret accepted, the size of an
+// HS256 signature.
+const MinHMACSecretLength = 32
+
+var (
+	// ErrNoKeys is returned by New when no key to verify tokens with is configured.
+	ErrNoKeys = errors.New("jwtauth: no verification keys configured")
+
+	// ErrWeakSecret is returned by New for HMAC secrets shorter than
+	// MinHMACSecretLength.
+	ErrWeakSecret = errors.New("jwtauth: HMAC secret too short")
+
+	// ErrInvalidToken is returned for tokens that are malformed, expired or
+	// not signed by a configured key.
+	ErrInvalidToken = errors.New("jwtauth: invalid token")
+)
+
+// Claims are the claims of an access token. The subject is the user id.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Verifier checks access tokens signed with HS256, RS256 or ES256.
+type Verifier struct {
+	hmacSecret   []byte
+	publicKeyPEM string
+	jwksFile     string
+	issuer       string
+	audience     string
+	leeway       time.Duration
+
+	// rsaKeys and ecKeys are indexed by kid; "" holds the key from config.
+	rsaKeys map[string]*rsa.PublicKey
+	ecKeys  map[string]*ecdsa.PublicKey
+	parser  *jwt.Parser
+}
+
+// New -.
+func New(opts ...Option) (*Verifier, error) {
+	v := &Verifier{
+		leeway:  _defaultLeeway,
+		rsaKeys: make(map[string]*rsa.PublicKey),
+		ecKeys:  make(map[string]*ecdsa.PublicKey),
+	}
+
+	// Custom options ...
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if v.publicKeyPEM != "" {
+		if err := v.addPEM(v.publicKeyPEM); err != nil {
+			return nil, fmt.Errorf("jwtauth - New - v.addPEM: %w", err)
+		}
+	}
+
+	if v.jwksFile != "" {
+		if err := v.loadJWKS(v.jwksFile); err != nil {
+			return nil, fmt.Errorf("jwtauth - New - v.loadJWKS: %w", err)
+		}
+	}
+
+	if v.hmacSecret != nil && len(v.hmacSecret) < MinHMACSecretLength {
+		return nil, ErrWeakSecret
+	}
+
+	var methods []string
+	if v.hmacSecret != nil {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(v.rsaKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(v.ecKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodES256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(v.audience))
+	}
+
+	v.parser = jwt.NewParser(parserOpts...)
+
+	return v, nil
+}
+
+// Verify checks the signature and registered claims of token and returns
+// its claims. Tokens without a subject are rejected.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := v.parser.ParseWithClaims(token, claims, v.key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// key picks the key to check the token's signature with from its alg and
+// kid. A token without a kid is checked against the key from config.
+func (v *Verifier) key(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.hmacSecret, nil
+	case *jwt.SigningMethodRSA:
+		if key, ok := v.rsaKeys[kid]; ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if key, ok := v.ecKeys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s key with kid %q", t.Method.Alg(), kid)
+}
+
+func (v *Verifier) addPEM(pem string) error {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(pem)); err == nil {
+		v.rsaKeys[""] = key
+
+		return nil
+	}
+
+	key, err := jwt.ParseECPublicKeyFromPEM([]byte(pem))
+	if err != nil {
+		return errors.New("public key is neither an RSA nor an EC PEM key")
+	}
+
+	if key.Curve != elliptic.P256() {
+		return errors.New("EC public key is not on P-256")
+	}
+
+	v.ecKeys[""] = key
+
+	return nil
+}
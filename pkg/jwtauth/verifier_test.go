@@ -0,0 +1,163 @@
+package jwtauth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fourth-exam/post-service-clean-arch/pkg/jwtauth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	_userId = "d0b69f3b-2021-4d91-8e13-c243d9eb5292"
+	_secret = "0123456789abcdef0123456789abcdef"
+)
+
+func claims(mod func(*jwtauth.Claims)) *jwtauth.Claims {
+	c := &jwtauth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   _userId,
+			Issuer:    "auth",
+			Audience:  jwt.ClaimStrings{"posts"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"moderator"},
+	}
+	if mod != nil {
+		mod(c)
+	}
+
+	return c
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, c *jwtauth.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestVerifyHS256(t *testing.T) {
+	t.Parallel()
+
+	v, err := jwtauth.New(jwtauth.HMACSecret(_secret), jwtauth.Issuer("auth"), jwtauth.Audience("posts"))
+	require.NoError(t, err)
+
+	got, err := v.Verify(sign(t, jwt.SigningMethodHS256, "", []byte(_secret), claims(nil)))
+	require.NoError(t, err)
+	require.Equal(t, _userId, got.Subject)
+	require.Equal(t, []string{"moderator"}, got.Roles)
+
+	for name, token := range map[string]string{
+		"wrong secret": sign(t, jwt.SigningMethodHS256, "", []byte("other"), claims(nil)),
+		"expired": sign(t, jwt.SigningMethodHS256, "", []byte(_secret), claims(func(c *jwtauth.Claims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+		})),
+		"no expiry": sign(t, jwt.SigningMethodHS256, "", []byte(_secret), claims(func(c *jwtauth.Claims) {
+			c.ExpiresAt = nil
+		})),
+		"no subject": sign(t, jwt.SigningMethodHS256, "", []byte(_secret), claims(func(c *jwtauth.Claims) {
+			c.Subject = ""
+		})),
+		"wrong issuer": sign(t, jwt.SigningMethodHS256, "", []byte(_secret), claims(func(c *jwtauth.Claims) {
+			c.Issuer = "someone"
+		})),
+		"wrong audience": sign(t, jwt.SigningMethodHS256, "", []byte(_secret), claims(func(c *jwtauth.Claims) {
+			c.Audience = jwt.ClaimStrings{"comments"}
+		})),
+		"unsigned": sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, claims(nil)),
+		"HS512":    sign(t, jwt.SigningMethodHS512, "", []byte(_secret), claims(nil)),
+		"garbage":  "not.a.token",
+	} {
+		_, err := v.Verify(token)
+		require.ErrorIs(t, err, jwtauth.ErrInvalidToken, name)
+	}
+}
+
+func TestVerifyPublicKey(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	v, err := jwtauth.New(jwtauth.PublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))))
+	require.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodRS256, "", rsaKey, claims(nil)))
+	require.NoError(t, err)
+
+	// The public key must not double as an HMAC secret.
+	_, err = v.Verify(sign(t, jwt.SigningMethodHS256, "", der, claims(nil)))
+	require.ErrorIs(t, err, jwtauth.ErrInvalidToken)
+}
+
+func TestVerifyJWKS(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	set, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": "AQAB"},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X.Bytes()), "y": b64(ecKey.Y.Bytes())},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": b64(rsaKey.N.Bytes()), "e": "AQAB"},
+	}})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, set, 0o600))
+
+	v, err := jwtauth.New(jwtauth.JWKSFile(path))
+	require.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims(nil)))
+	require.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodES256, "ec-1", ecKey, claims(nil)))
+	require.NoError(t, err)
+
+	for _, kid := range []string{"", "enc-1", "ec-1"} {
+		_, err = v.Verify(sign(t, jwt.SigningMethodRS256, kid, rsaKey, claims(nil)))
+		require.ErrorIs(t, err, jwtauth.ErrInvalidToken, kid)
+	}
+}
+
+func TestNewWithoutKeys(t *testing.T) {
+	t.Parallel()
+
+	_, err := jwtauth.New(jwtauth.HMACSecret(""), jwtauth.PublicKey(""))
+	require.ErrorIs(t, err, jwtauth.ErrNoKeys)
+}
+
+func TestNewWithWeakSecret(t *testing.T) {
+	t.Parallel()
+
+	_, err := jwtauth.New(jwtauth.HMACSecret(_secret[:jwtauth.MinHMACSecretLength-1]))
+	require.ErrorIs(t, err, jwtauth.ErrWeakSecret)
+}
@@ -23,4 +23,20 @@ func ConnTimeout(timeout time.Duration) Option {
 	return func(p *Postgres) {
 		p.connTimeout = timeout
 	}
-}
\ No newline at end of file
+}
+
+// IsolationLevel sets the isolation level of WithinTx transactions:
+// "read committed" (the server default), "repeatable read" or "serializable".
+func IsolationLevel(level string) Option {
+	return func(p *Postgres) {
+		p.isoLevel = level
+	}
+}
+
+// TxRetries sets how many times WithinTx retries a transaction that failed
+// with a serialization error.
+func TxRetries(retries int) Option {
+	return func(p *Postgres) {
+		p.txRetries = retries
+	}
+}
@@ -21,9 +21,12 @@ type Postgres struct {
 	maxPoolSize  int
 	connAttempts int
 	connTimeout  time.Duration
+	isoLevel     string
+	txRetries    int
 
 	Builder squirrel.StatementBuilderType
 	Pool    *pgxpool.Pool
+	*TxManager
 }
 
 // New ..
@@ -32,6 +35,7 @@ func New(url string, opts ...Option) (*Postgres, error) {
 		maxPoolSize:  -_defaultMaxPoolSize,
 		connAttempts: _defaultConnAttempts,
 		connTimeout:  _defaultConnTimeout,
+		txRetries:    _defaultTxRetries,
 	}
 
 	// Custom options
@@ -41,6 +45,11 @@ func New(url string, opts ...Option) (*Postgres, error) {
 
 	pg.Builder = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
+	isoLevel, err := parseIsoLevel(pg.isoLevel)
+	if err != nil {
+		return nil, fmt.Errorf("postgres - NewPostgres - parseIsoLevel: %w", err)
+	}
+
 	poolConfig, err := pgxpool.ParseConfig(url)
 	if err != nil {
 		return nil, fmt.Errorf("postgres - NewPostgres - pgxpool.ParseConfig: %w", err)
@@ -65,6 +74,13 @@ func New(url string, opts ...Option) (*Postgres, error) {
 		return nil, fmt.Errorf("postgres - NewPostgres - connAttempts == 0: %w", err)
 	}
 
+	pg.TxManager = &TxManager{
+		pool:       pg.Pool,
+		isoLevel:   isoLevel,
+		retries:    pg.txRetries,
+		retryDelay: _defaultTxRetryDelay,
+	}
+
 	return pg, nil
 }
 
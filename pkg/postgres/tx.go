@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	_defaultTxRetries    = 3
+	_defaultTxRetryDelay = 10 * time.Millisecond
+
+	_serializationFailure = "40001"
+)
+
+// Querier runs statements on the pool or inside a transaction.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+type txKey struct{}
+
+// txState is what a context carries of its transaction.
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func()
+}
+
+// TxManager runs functions in a transaction carried by their context, so
+// repositories called with that context join it.
+type TxManager struct {
+	pool       *pgxpool.Pool
+	isoLevel   pgx.TxIsoLevel
+	retries    int
+	retryDelay time.Duration
+}
+
+// WithinTx runs fn in a transaction, committing when it returns nil and
+// rolling back otherwise. When ctx already carries a transaction fn joins it,
+// and the outermost call decides. A transaction failing with a serialization
+// error (40001) is retried from the start, so fn must be safe to run again.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTx(ctx) {
+		return fn(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || !isSerializationFailure(err) || attempt >= m.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(m.retryDelay * time.Duration(attempt+1)):
+		}
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: m.isoLevel})
+	if err != nil {
+		return fmt.Errorf("postgres - WithinTx - m.pool.BeginTx: %w", err)
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	state := &txState{tx: tx}
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("postgres - WithinTx - tx.Commit: %w", err)
+	}
+
+	for _, hook := range state.afterCommit {
+		hook()
+	}
+
+	return nil
+}
+
+// InTx reports whether ctx carries a transaction.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+
+	return ok
+}
+
+// AfterCommit runs fn once the transaction carried by ctx commits, or right
+// away outside of one. fn doesn't run if the transaction rolls back.
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+
+		return
+	}
+
+	fn()
+}
+
+// Querier returns the transaction carried by ctx, or the pool outside of one.
+func (p *Postgres) Querier(ctx context.Context) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+
+	return p.Pool
+}
+
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == _serializationFailure
+}
+
+func parseIsoLevel(level string) (pgx.TxIsoLevel, error) {
+	switch l := pgx.TxIsoLevel(level); l {
+	case "", pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable, pgx.ReadUncommitted:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unknown isolation level %q", level)
+	}
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var errFn = errors.New("fn failed")
+
+func TestParseIsoLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, level := range []string{"", "read committed", "repeatable read", "serializable", "read uncommitted"} {
+		got, err := parseIsoLevel(level)
+		require.NoError(t, err, level)
+		require.Equal(t, pgx.TxIsoLevel(level), got)
+	}
+
+	_, err := parseIsoLevel("snapshot")
+	require.Error(t, err)
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, isSerializationFailure(fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: _serializationFailure})))
+	require.False(t, isSerializationFailure(&pgconn.PgError{Code: "23505"}))
+	require.False(t, isSerializationFailure(errFn))
+}
+
+func TestAfterCommitOutsideTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	require.False(t, InTx(ctx))
+
+	ran := false
+	AfterCommit(ctx, func() { ran = true })
+	require.True(t, ran, "hooks run right away outside a transaction")
+}
+
+// TestWithinTx runs transactions on the disposable database at PG_URL, in a
+// table of its own.
+func TestWithinTx(t *testing.T) {
+	url := os.Getenv("PG_URL")
+	if url == "" {
+		t.Skip("PG_URL is not set")
+	}
+
+	pg, err := New(url, MaxPoolSize(2))
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	ctx := context.Background()
+	table := "tx_test_" + uuid.NewString()[:8]
+
+	_, err = pg.Pool.Exec(ctx, "CREATE TABLE "+table+" (n int NOT NULL)")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := pg.Pool.Exec(context.Background(), "DROP TABLE "+table)
+		require.NoError(t, err)
+	})
+
+	insert := func(ctx context.Context, n int) {
+		t.Helper()
+
+		_, err := pg.Querier(ctx).Exec(ctx, "INSERT INTO "+table+" (n) VALUES ($1)", n)
+		require.NoError(t, err)
+	}
+
+	count := func(n int) int {
+		t.Helper()
+
+		var c int
+		require.NoError(t, pg.Pool.QueryRow(ctx, "SELECT count(*) FROM "+table+" WHERE n = $1", n).Scan(&c))
+
+		return c
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		committed := false
+
+		err := pg.WithinTx(ctx, func(ctx context.Context) error {
+			require.True(t, InTx(ctx))
+			insert(ctx, 1)
+			AfterCommit(ctx, func() { committed = true })
+
+			require.Zero(t, count(1), "others don't see the row before the commit")
+			require.False(t, committed, "hooks wait for the commit")
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, count(1))
+		require.True(t, committed)
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		committed := false
+
+		err := pg.WithinTx(ctx, func(ctx context.Context) error {
+			insert(ctx, 2)
+			AfterCommit(ctx, func() { committed = true })
+
+			return errFn
+		})
+		require.ErrorIs(t, err, errFn)
+		require.Zero(t, count(2))
+		require.False(t, committed, "hooks don't run on rollback")
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		require.Panics(t, func() {
+			_ = pg.WithinTx(ctx, func(ctx context.Context) error {
+				insert(ctx, 3)
+
+				panic("fn panicked")
+			})
+		})
+		require.Zero(t, count(3))
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		var outer pgx.Tx
+		committed := false
+
+		err := pg.WithinTx(ctx, func(ctx context.Context) error {
+			outer = pg.Querier(ctx).(pgx.Tx)
+
+			err := pg.WithinTx(ctx, func(ctx context.Context) error {
+				require.Same(t, outer, pg.Querier(ctx), "nested calls join the transaction")
+				insert(ctx, 4)
+				AfterCommit(ctx, func() { committed = true })
+
+				return nil
+			})
+			require.NoError(t, err)
+			require.False(t, committed, "the outermost call commits")
+
+			return errFn
+		})
+		require.ErrorIs(t, err, errFn)
+		require.Zero(t, count(4), "the outermost call rolls back what nested calls did")
+		require.False(t, committed)
+	})
+
+	t.Run("retry on serialization failure", func(t *testing.T) {
+		attempts := 0
+
+		err := pg.WithinTx(ctx, func(ctx context.Context) error {
+			attempts++
+			insert(ctx, 5)
+
+			if attempts == 1 {
+				return fmt.Errorf("conflict: %w", &pgconn.PgError{Code: _serializationFailure})
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, attempts)
+		require.Equal(t, 1, count(5), "the failed attempt rolled back")
+	})
+
+	t.Run("retries run out", func(t *testing.T) {
+		attempts := 0
+
+		err := pg.WithinTx(ctx, func(context.Context) error {
+			attempts++
+
+			return &pgconn.PgError{Code: _serializationFailure}
+		})
+		require.True(t, isSerializationFailure(err))
+		require.Equal(t, _defaultTxRetries+1, attempts)
+	})
+
+	t.Run("other errors aren't retried", func(t *testing.T) {
+		attempts := 0
+
+		err := pg.WithinTx(ctx, func(context.Context) error {
+			attempts++
+
+			return errFn
+		})
+		require.ErrorIs(t, err, errFn)
+		require.Equal(t, 1, attempts)
+	})
+}
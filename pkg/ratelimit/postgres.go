@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// _refilled is the tokens of an existing bucket once refilled up to now.
+// $2 is the limit and $3 the tokens added a second.
+const _refilled = `LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * $3::float8)`
+
+// _take takes a token from the bucket of key $1 in one statement, so
+// concurrent requests from any instance see each other. A bucket is full
+// again $4 seconds after its last request at the latest.
+var _take = fmt.Sprintf(`INSERT INTO rate_limits AS b (key, tokens, allowed, updated_at, expires_at)
+	VALUES ($1, $2::float8 - 1, true, now(), now() + make_interval(secs => $4::float8))
+	ON CONFLICT (key) DO UPDATE SET
+		tokens = CASE WHEN %[1]s >= 1 THEN %[1]s - 1 ELSE %[1]s END,
+		allowed = %[1]s >= 1,
+		updated_at = now(),
+		expires_at = now() + make_interval(secs => $4::float8)
+	RETURNING tokens, allowed`, _refilled)
+
+// _peek returns the tokens of the bucket of key $1 once refilled.
+var _peek = fmt.Sprintf(`SELECT %s FROM rate_limits AS b WHERE b.key = $1`, _refilled)
+
+// Postgres keeps the buckets in the rate_limits table, so every instance of
+// the service sharing the database shares the limits.
+type Postgres struct {
+	pg *postgres.Postgres
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+var _ Store = (*Postgres)(nil)
+
+// NewPostgres -.
+func NewPostgres(pg *postgres.Postgres) *Postgres {
+	return &Postgres{pg: pg}
+}
+
+// Take takes a token from the bucket of key.
+func (p *Postgres) Take(ctx context.Context, key string, rate Rate) (Result, error) {
+	if err := p.sweep(ctx); err != nil {
+		return Result{}, err
+	}
+
+	var (
+		tokens  float64
+		allowed bool
+	)
+
+	err := p.pg.Pool.QueryRow(ctx, _take, key, float64(rate.Limit), float64(rate.Limit)/rate.Period.Seconds(),
+		rate.Period.Seconds()).Scan(&tokens, &allowed)
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit - Postgres - Take - p.pg.Pool.QueryRow: %w", err)
+	}
+
+	return result(rate, tokens, allowed), nil
+}
+
+// Peek returns the state of the bucket of key.
+func (p *Postgres) Peek(ctx context.Context, key string, rate Rate) (Result, error) {
+	tokens := float64(rate.Limit)
+
+	err := p.pg.Pool.QueryRow(ctx, _peek, key, float64(rate.Limit), float64(rate.Limit)/rate.Period.Seconds()).
+		Scan(&tokens)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, fmt.Errorf("ratelimit - Postgres - Peek - p.pg.Pool.QueryRow: %w", err)
+	}
+
+	return result(rate, tokens, tokens >= 1), nil
+}
+
+// sweep deletes the buckets that are full by now, at most every
+// _sweepInterval.
+func (p *Postgres) sweep(ctx context.Context) error {
+	p.mu.Lock()
+	if time.Since(p.lastSweep) < _sweepInterval {
+		p.mu.Unlock()
+
+		return nil
+	}
+	p.lastSweep = time.Now()
+	p.mu.Unlock()
+
+	_, err := p.pg.Pool.Exec(ctx, "DELETE FROM rate_limits WHERE expires_at < now()")
+	if err != nil {
+		return fmt.Errorf("ratelimit - Postgres - sweep - p.pg.Pool.Exec: %w", err)
+	}
+
+	return nil
+}
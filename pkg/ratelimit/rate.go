@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRate parses a rate written "limit/period", such as "30/1m" for 30
+// requests a minute.
+func ParseRate(s string) (Rate, error) {
+	limit, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("ratelimit - ParseRate: %q is not limit/period", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("ratelimit - ParseRate: limit of %q is not a positive number", s)
+	}
+
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("ratelimit - ParseRate: period of %q is not a positive duration", s)
+	}
+
+	return Rate{Limit: n, Period: d}, nil
+}
+
+// Policy describes the rate as a RateLimit-Policy header does, such as
+// "30;w=60" for 30 requests a minute.
+func (r Rate) Policy() string {
+	return strconv.Itoa(r.Limit) + ";w=" + strconv.FormatInt(int64(r.Period.Round(time.Second)/time.Second), 10)
+}
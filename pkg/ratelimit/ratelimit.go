@@ -0,0 +1,152 @@
+// Package ratelimit limits how often a key may do something with token
+// buckets: a bucket holds up to Limit tokens, refills at Limit per Period
+// and every request takes a token.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// _sweepInterval is how often Memory forgets buckets that have refilled.
+const _sweepInterval = time.Minute
+
+// Rate is Limit requests per Period, in bursts of up to Limit.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// PerMinute -.
+func PerMinute(n int) Rate {
+	return Rate{Limit: n, Period: time.Minute}
+}
+
+// Result is the state of a bucket after taking a token from it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available, when not Allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps the buckets.
+type Store interface {
+	// Take takes a token from the bucket of key, filled at rate.
+	Take(ctx context.Context, key string, rate Rate) (Result, error)
+	// Peek returns the state of the bucket of key without taking a token;
+	// Allowed reports whether one is available.
+	Peek(ctx context.Context, key string, rate Rate) (Result, error)
+}
+
+var _ Store = (*Memory)(nil)
+
+// Memory keeps the buckets in memory, so each instance of the service
+// limits on its own.
+type Memory struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+	full   time.Time
+}
+
+// NewMemory -.
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket)}
+}
+
+// Take takes a token from the bucket of key.
+func (m *Memory) Take(_ context.Context, key string, rate Rate) (Result, error) {
+	return m.take(key, rate, time.Now()), nil
+}
+
+func (m *Memory) take(key string, rate Rate, now time.Time) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rate.Limit), at: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = b.refilled(rate, now)
+	b.at = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	res := result(rate, b.tokens, allowed)
+	b.full = now.Add(res.Reset)
+
+	return res
+}
+
+// Peek returns the state of the bucket of key.
+func (m *Memory) Peek(_ context.Context, key string, rate Rate) (Result, error) {
+	return m.peek(key, rate, time.Now()), nil
+}
+
+func (m *Memory) peek(key string, rate Rate, now time.Time) Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tokens := float64(rate.Limit)
+	if b, ok := m.buckets[key]; ok {
+		tokens = b.refilled(rate, now)
+	}
+
+	return result(rate, tokens, tokens >= 1)
+}
+
+// refilled returns the tokens of the bucket once refilled at rate up to now.
+func (b *bucket) refilled(rate Rate, now time.Time) float64 {
+	perToken := rate.Period / time.Duration(rate.Limit)
+
+	return math.Min(float64(rate.Limit), b.tokens+float64(now.Sub(b.at))/float64(perToken))
+}
+
+// result is the state of a bucket filled at rate holding tokens.
+func result(rate Rate, tokens float64, allowed bool) Result {
+	perToken := rate.Period / time.Duration(rate.Limit)
+
+	res := Result{
+		Allowed:   allowed,
+		Limit:     rate.Limit,
+		Remaining: int(tokens),
+		Reset:     time.Duration((float64(rate.Limit) - tokens) * float64(perToken)),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+
+	return res
+}
+
+// sweep forgets the buckets that are full by now, which behave like new ones.
+func (m *Memory) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < _sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"fourth-exam/post-service-clean-arch/pkg/postgres"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTake(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m    = NewMemory()
+		rate = Rate{Limit: 3, Period: 3 * time.Second}
+		now  = time.Now()
+	)
+
+	for i := 2; i >= 0; i-- {
+		res := m.take("a", rate, now)
+		require.True(t, res.Allowed)
+		require.Equal(t, i, res.Remaining)
+	}
+
+	res := m.take("a", rate, now)
+	require.False(t, res.Allowed)
+	require.Equal(t, time.Second, res.RetryAfter)
+	require.Equal(t, 3*time.Second, res.Reset)
+
+	require.True(t, m.take("b", rate, now).Allowed, "keys have their own buckets")
+
+	res = m.take("a", rate, now.Add(1500*time.Millisecond))
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+
+	res = m.take("a", rate, now.Add(time.Hour))
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Remaining, "bursts are capped at the limit")
+}
+
+func TestMemoryPeek(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m    = NewMemory()
+		rate = Rate{Limit: 2, Period: 2 * time.Second}
+		now  = time.Now()
+	)
+
+	res := m.peek("a", rate, now)
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Remaining, "a new bucket is full")
+	require.Empty(t, m.buckets, "peeking creates no bucket")
+
+	m.take("a", rate, now)
+	m.take("a", rate, now)
+
+	for i := 0; i < 2; i++ {
+		res = m.peek("a", rate, now.Add(500*time.Millisecond))
+		require.False(t, res.Allowed)
+		require.Equal(t, 0, res.Remaining)
+		require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	}
+
+	require.True(t, m.peek("a", rate, now.Add(time.Second)).Allowed)
+}
+
+func TestMemorySweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		m    = NewMemory()
+		rate = PerMinute(60)
+		now  = time.Now()
+	)
+
+	m.take("a", rate, now)
+	m.take("b", rate, now.Add(_sweepInterval))
+	m.take("c", rate, now.Add(_sweepInterval+500*time.Millisecond))
+
+	require.Len(t, m.buckets, 2)
+	require.NotContains(t, m.buckets, "a")
+}
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	rate, err := ParseRate("30/1m")
+	require.NoError(t, err)
+	require.Equal(t, PerMinute(30), rate)
+	require.Equal(t, "30;w=60", rate.Policy())
+
+	rate, err = ParseRate(" 5 / 1s ")
+	require.NoError(t, err)
+	require.Equal(t, Rate{Limit: 5, Period: time.Second}, rate)
+
+	for _, s := range []string{"", "30", "0/1m", "-1/1m", "x/1m", "30/", "30/0s", "30/minute"} {
+		_, err := ParseRate(s)
+		require.Error(t, err, s)
+	}
+}
+
+// TestPostgresTake runs against the disposable database at PG_URL, with the
+// migrations applied.
+func TestPostgresTake(t *testing.T) {
+	url := os.Getenv("PG_URL")
+	if url == "" {
+		t.Skip("PG_URL is not set")
+	}
+
+	pg, err := postgres.New(url)
+	require.NoError(t, err)
+	t.Cleanup(pg.Close)
+
+	var (
+		store = NewPostgres(pg)
+		key   = "test:" + uuid.NewString()
+		rate  = Rate{Limit: 3, Period: time.Hour}
+		ctx   = context.Background()
+	)
+
+	t.Cleanup(func() {
+		_, err := pg.Pool.Exec(ctx, "DELETE FROM rate_limits WHERE key = $1", key)
+		require.NoError(t, err)
+	})
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, key, rate)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		require.Equal(t, i, res.Remaining)
+	}
+
+	res, err := store.Take(ctx, key, rate)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.InDelta(t, 20*time.Minute, res.RetryAfter, float64(time.Second))
+	require.InDelta(t, time.Hour, res.Reset, float64(time.Second))
+
+	res, err = store.Peek(ctx, key, rate)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.InDelta(t, 20*time.Minute, res.RetryAfter, float64(time.Second))
+
+	res, err = store.Peek(ctx, "test:"+uuid.NewString(), rate)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 3, res.Remaining, "a missing bucket is full")
+}
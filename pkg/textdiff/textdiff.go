@@ -0,0 +1,116 @@
+package textdiff
+
+import "strings"
+
+// Op is the kind of an Edit.
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// _maxCells bounds the LCS table. Beyond it the changed middle of the two
+// texts is reported as a whole delete followed by a whole insert.
+const _maxCells = 4 << 20
+
+// Edit is one line of a diff.
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the line-by-line edits turning a into b, keeping the
+// longest common subsequence of lines unchanged.
+func Lines(a, b string) []Edit {
+	x, y := split(a), split(b)
+
+	// Common prefix and suffix need no table.
+	prefix := 0
+	for prefix < len(x) && prefix < len(y) && x[prefix] == y[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(x)-prefix && suffix < len(y)-prefix &&
+		x[len(x)-1-suffix] == y[len(y)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(x)+len(y))
+	for _, line := range x[:prefix] {
+		edits = append(edits, Edit{Equal, line})
+	}
+
+	edits = append(edits, middle(x[prefix:len(x)-suffix], y[prefix:len(y)-suffix])...)
+
+	for _, line := range x[len(x)-suffix:] {
+		edits = append(edits, Edit{Equal, line})
+	}
+
+	return edits
+}
+
+// middle diffs the lines between the common prefix and suffix.
+func middle(x, y []string) []Edit {
+	edits := make([]Edit, 0, len(x)+len(y))
+
+	if len(x)*len(y) > _maxCells {
+		for _, line := range x {
+			edits = append(edits, Edit{Delete, line})
+		}
+		for _, line := range y {
+			edits = append(edits, Edit{Insert, line})
+		}
+
+		return edits
+	}
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			edits = append(edits, Edit{Equal, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, Edit{Delete, x[i]})
+			i++
+		default:
+			edits = append(edits, Edit{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		edits = append(edits, Edit{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		edits = append(edits, Edit{Insert, y[j]})
+	}
+
+	return edits
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,148 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b string
+		want []Edit
+	}{
+		{"both empty", "", "", []Edit{}},
+		{"from empty", "", "a\nb\n", []Edit{{Insert, "a"}, {Insert, "b"}}},
+		{"to empty", "a\nb", "", []Edit{{Delete, "a"}, {Delete, "b"}}},
+		{"same", "a\nb\n", "a\nb", []Edit{{Equal, "a"}, {Equal, "b"}}},
+		{
+			"changed line",
+			"a\nb\nc", "a\nB\nc",
+			[]Edit{{Equal, "a"}, {Delete, "b"}, {Insert, "B"}, {Equal, "c"}},
+		},
+		{
+			"inserted and deleted lines",
+			"a\nb\nc\nd", "a\nc\nd\ne",
+			[]Edit{{Equal, "a"}, {Delete, "b"}, {Equal, "c"}, {Equal, "d"}, {Insert, "e"}},
+		},
+		{
+			"longest common subsequence kept",
+			"x\na\nb\nc\ny", "z\nb\nc\na\nw",
+			[]Edit{
+				{Delete, "x"}, {Delete, "a"}, {Insert, "z"}, {Equal, "b"}, {Equal, "c"},
+				{Delete, "y"}, {Insert, "a"}, {Insert, "w"},
+			},
+		},
+		{
+			"blank lines",
+			"a\n\nb", "a\nb\n\n",
+			[]Edit{{Equal, "a"}, {Delete, ""}, {Equal, "b"}, {Insert, ""}},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			edits := Lines(tc.a, tc.b)
+			require.Equal(t, tc.want, edits)
+
+			a, b := apply(edits)
+			require.Equal(t, split(tc.a), a)
+			require.Equal(t, split(tc.b), b)
+		})
+	}
+}
+
+func TestLinesMaxCells(t *testing.T) {
+	t.Parallel()
+
+	// texts returns n distinct lines each, sharing one line in the middle and
+	// a common prefix and suffix, which the table doesn't count.
+	texts := func(n int) (string, string) {
+		x, y := []string{"head"}, []string{"head"}
+		for i := 0; i < n; i++ {
+			if i == n/2 {
+				x, y = append(x, "shared"), append(y, "shared")
+
+				continue
+			}
+
+			x, y = append(x, fmt.Sprintf("a%d", i)), append(y, fmt.Sprintf("b%d", i))
+		}
+
+		return strings.Join(append(x, "tail"), "\n"), strings.Join(append(y, "tail"), "\n")
+	}
+
+	tests := []struct {
+		name   string
+		n      int
+		shared bool
+	}{
+		{"within the bound", 2048, true},
+		{"beyond the bound", 2049, false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.n*tc.n > _maxCells, !tc.shared)
+
+			a, b := texts(tc.n)
+			edits := Lines(a, b)
+
+			got, want := apply(edits)
+			require.Equal(t, split(a), got)
+			require.Equal(t, split(b), want)
+
+			require.Equal(t, Edit{Equal, "head"}, edits[0])
+			require.Equal(t, Edit{Equal, "tail"}, edits[len(edits)-1])
+			require.Equal(t, tc.shared, contains(edits, Edit{Equal, "shared"}))
+
+			if !tc.shared {
+				middle := edits[1 : len(edits)-1]
+				for i, e := range middle {
+					want := Delete
+					if i >= tc.n {
+						want = Insert
+					}
+					require.Equal(t, want, e.Op, "a whole delete followed by a whole insert")
+				}
+			}
+		})
+	}
+}
+
+// apply returns the lines the edits turn from and into.
+func apply(edits []Edit) (from, to []string) {
+	for _, e := range edits {
+		if e.Op != Insert {
+			from = append(from, e.Text)
+		}
+		if e.Op != Delete {
+			to = append(to, e.Text)
+		}
+	}
+
+	return from, to
+}
+
+func contains(edits []Edit, want Edit) bool {
+	for _, e := range edits {
+		if e == want {
+			return true
+		}
+	}
+
+	return false
+}